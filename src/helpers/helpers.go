@@ -1,11 +1,441 @@
 package helpers
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
 type XObject interface {
 	Validate(interface{}) (bool, []error)
 	String() string
 }
 
+type XRequirer interface {
+	IsRequired() bool
+}
+
+type XOptional interface {
+	IsOptional() bool
+}
+
+type XContextValidator interface {
+	ValidateContext(val interface{}, ctx *ValidationContext) (bool, []error)
+}
+
+func ValidateContext(xo XObject, val interface{}, ctx *ValidationContext) (bool, []error) {
+	if cv, ok := xo.(XContextValidator); ok {
+		return cv.ValidateContext(val, ctx)
+	}
+
+	return xo.Validate(val)
+}
+
+type ValidationContext struct {
+	Depth    int
+	JSONTags bool
+	visiting map[interface{}]bool
+}
+
+func (ctx *ValidationContext) UseJSONTags(jsonTags bool) func() {
+	if !jsonTags || ctx.JSONTags {
+		return func() {}
+	}
+
+	ctx.JSONTags = true
+	return func() { ctx.JSONTags = false }
+}
+
+func (ctx *ValidationContext) Enter(key interface{}) bool {
+	if ctx.visiting[key] {
+		return false
+	}
+
+	if ctx.visiting == nil {
+		ctx.visiting = make(map[interface{}]bool)
+	}
+
+	ctx.visiting[key] = true
+	return true
+}
+
+func (ctx *ValidationContext) Leave(key interface{}) {
+	delete(ctx.visiting, key)
+}
+
 type XValidation[T any] struct {
-	E error
+	E *ValidationError
 	F func(T) bool
+	C func(T) error
+}
+
+func (v XValidation[T]) Check(value T, raw interface{}) *ValidationError {
+	if v.C != nil {
+		if err := v.C(value); err != nil {
+			ve := v.E.WithValue(raw)
+			ve.Message = err.Error()
+			return ve
+		}
+
+		return nil
+	}
+
+	if !v.F(value) {
+		return v.E.WithValue(raw)
+	}
+
+	return nil
+}
+
+type ValidationError struct {
+	Path    string
+	Rule    string
+	Params  map[string]interface{}
+	Value   interface{}
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+func (e *ValidationError) WithValue(value interface{}) *ValidationError {
+	err := *e
+	err.Value = value
+	return &err
+}
+
+func (e *ValidationError) Redact() *ValidationError {
+	return e.WithValue(nil)
+}
+
+type ValidationErrors []*ValidationError
+
+func NewValidationErrors(errs []error) ValidationErrors {
+	validationErrors := make(ValidationErrors, 0, len(errs))
+
+	for _, err := range errs {
+		validationErrors = append(validationErrors, WithPath("", nil, err))
+	}
+
+	return validationErrors
+}
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+
+	for _, err := range ve {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (ve ValidationErrors) Redact() ValidationErrors {
+	redacted := make(ValidationErrors, 0, len(ve))
+
+	for _, err := range ve {
+		redacted = append(redacted, err.Redact())
+	}
+
+	return redacted
+}
+
+func NewError(rule string, params map[string]interface{}, message string) *ValidationError {
+	return &ValidationError{Rule: rule, Params: params, Message: message}
+}
+
+func NullError() *ValidationError {
+	return &ValidationError{Rule: "NotNull", Message: "must not be null"}
+}
+
+func TypeError(expected string, value interface{}) *ValidationError {
+	return &ValidationError{Rule: "Type", Params: map[string]interface{}{"expected": expected}, Value: value, Message: "invalid type"}
+}
+
+func WithPath(key string, value interface{}, err error) *ValidationError {
+	if ve, ok := err.(*ValidationError); ok {
+		pathErr := *ve
+		pathErr.Path = JoinPath(key, ve.Path)
+		return &pathErr
+	}
+
+	return &ValidationError{Path: key, Value: value, Message: err.Error()}
+}
+
+func Indirect(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(val)
+
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv.Interface()
+}
+
+func JoinPath(prefix string, path string) string {
+	if prefix == "" {
+		return path
+	}
+
+	if path == "" {
+		return prefix
+	}
+
+	if path[0] == '[' {
+		return prefix + path
+	}
+
+	return prefix + "." + path
+}
+
+type TagError struct {
+	Field string
+	Rule  string
+	Arg   string
+	Err   error
+}
+
+func (e *TagError) Error() string {
+	msg := "invalid tag"
+
+	if e.Field != "" {
+		msg += fmt.Sprintf(" on field %q", e.Field)
+	}
+
+	if e.Rule != "" {
+		msg += fmt.Sprintf(": rule %q", e.Rule)
+	}
+
+	if e.Arg != "" {
+		msg += fmt.Sprintf(" with argument %q", e.Arg)
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+func WithTagField(field string, err error) error {
+	if te, ok := err.(*TagError); ok {
+		fieldErr := *te
+		fieldErr.Field = JoinPath(field, te.Field)
+		return &fieldErr
+	}
+
+	return &TagError{Field: field, Err: err}
+}
+
+func NoTagArgs(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected no arguments, got %d", len(args))
+	}
+
+	return nil
+}
+
+func StringTagArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+
+	return args[0], nil
+}
+
+func IntTagArg(args []string) (int, error) {
+	arg, err := StringTagArg(args)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(arg)
+}
+
+func BigIntTagArg(args []string) (*big.Int, error) {
+	arg, err := StringTagArg(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBigInt(arg)
+}
+
+func ParseBigInt(arg string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(arg, 10)
+
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", arg)
+	}
+
+	return n, nil
+}
+
+func FloatTagArg(args []string) (float64, error) {
+	arg, err := StringTagArg(args)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(arg, 64)
+}
+
+func BoolTagArg(args []string) (bool, error) {
+	arg, err := StringTagArg(args)
+
+	if err != nil {
+		return false, err
+	}
+
+	return strconv.ParseBool(arg)
+}
+
+func ListTagArg[T any](args []string, parse func(string) (T, error)) ([]T, error) {
+	values := make([]T, 0, len(args))
+
+	if len(args) == 1 && strings.HasPrefix(args[0], "[") {
+		err := json.Unmarshal([]byte(args[0]), &values)
+		return values, err
+	}
+
+	for _, arg := range args {
+		value, err := parse(arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func ParseTags(validationTags []string) ([]xtag.Rule, error) {
+	rules := make([]xtag.Rule, 0, len(validationTags))
+
+	for _, v := range validationTags {
+		parsed, err := xtag.Parse(v)
+
+		if err != nil {
+			return nil, &TagError{Err: err}
+		}
+
+		rules = append(rules, parsed...)
+	}
+
+	return rules, nil
+}
+
+var ErrUnknownRule = errors.New("unknown rule")
+
+var ErrRuleExists = errors.New("rule already registered")
+
+type RuleFactory[T any] func(args []string) (XValidation[T], error)
+
+type RuleRegistry[T any] struct {
+	mu       sync.RWMutex
+	builtin  map[string]bool
+	handlers map[string]RuleFactory[T]
+}
+
+func NewRuleRegistry[T any](builtin ...string) *RuleRegistry[T] {
+	registry := &RuleRegistry[T]{builtin: make(map[string]bool), handlers: make(map[string]RuleFactory[T])}
+
+	for _, name := range builtin {
+		registry.builtin[name] = true
+	}
+
+	return registry
+}
+
+func (r *RuleRegistry[T]) Register(name string, factory RuleFactory[T]) error {
+	if !xtag.IsName(name) {
+		return fmt.Errorf("invalid rule name %q", name)
+	}
+
+	if factory == nil {
+		return fmt.Errorf("nil factory for rule %q", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[name]; ok || r.builtin[name] {
+		return fmt.Errorf("%w: %q", ErrRuleExists, name)
+	}
+
+	r.handlers[name] = factory
+	return nil
+}
+
+func (r *RuleRegistry[T]) Lookup(name string) (RuleFactory[T], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.handlers[name]
+	return factory, ok
+}
+
+func (r *RuleRegistry[T]) MustInline(name string, validation XValidation[T]) XValidation[T] {
+	if validation.F == nil && validation.C == nil {
+		panic(fmt.Sprintf("nil check for rule %q", name))
+	}
+
+	if r.builtin[name] {
+		panic(fmt.Sprintf("%v: %q is a built-in rule", ErrRuleExists, name))
+	}
+
+	return validation
+}
+
+func (r *RuleRegistry[T]) Build(name string, args []string) (XValidation[T], error) {
+	factory, ok := r.Lookup(name)
+
+	if !ok {
+		return XValidation[T]{}, ErrUnknownRule
+	}
+
+	validation, err := factory(args)
+
+	if err != nil {
+		return validation, err
+	}
+
+	if validation.F == nil && validation.C == nil {
+		return validation, fmt.Errorf("rule %q has no validation function", name)
+	}
+
+	if validation.E == nil {
+		validation.E = NewError(name, nil, "is invalid")
+	} else if validation.E.Rule == "" {
+		e := *validation.E
+		e.Rule = name
+		validation.E = &e
+	}
+
+	return validation, nil
 }
@@ -0,0 +1,97 @@
+package helpers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/radchukd/go-xschema/src/helpers"
+)
+
+func TestJoinPath(t *testing.T) {
+	cases := [][3]string{
+		{"", "name", "name"},
+		{"user", "", "user"},
+		{"user", "name", "user.name"},
+		{"emails", "[2]", "emails[2]"},
+		{"[0]", "zip", "[0].zip"},
+	}
+
+	for _, c := range cases {
+		if path := helpers.JoinPath(c[0], c[1]); path != c[2] {
+			t.Errorf("JoinPath(%s, %s) -> %s; want %s", c[0], c[1], path, c[2])
+		}
+	}
+}
+
+func TestWithPath(t *testing.T) {
+	err := helpers.NewError("Min", map[string]interface{}{"min": 3}, "too short").WithValue("ab")
+	ve := helpers.WithPath("user", nil, helpers.WithPath("name", "ab", err))
+
+	if ve.Path != "user.name" || ve.Rule != "Min" || ve.Value != "ab" {
+		t.Errorf("WithPath(%v) -> %+v; want user.name Min ab", err, ve)
+	}
+
+	if err.Path != "" {
+		t.Errorf("WithPath(%v) mutated the original error", err)
+	}
+
+	ve = helpers.WithPath("name", "ab", errors.New("custom"))
+
+	if ve.Path != "name" || ve.Message != "custom" || ve.Value != "ab" {
+		t.Errorf("WithPath(custom) -> %+v; want name custom ab", ve)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	errs := helpers.NewValidationErrors([]error{
+		helpers.WithPath("password", nil, helpers.NewError("Min", nil, "too short").WithValue("secret")),
+		errors.New("invalid"),
+	})
+
+	if msg := errs.Error(); msg != "password: too short; invalid" {
+		t.Errorf("Error() -> %s; want \"password: too short; invalid\"", msg)
+	}
+
+	redacted := errs.Redact()
+
+	if redacted[0].Value != nil || errs[0].Value != "secret" {
+		t.Errorf("Redact() -> %v; want nil value and untouched original", redacted[0].Value)
+	}
+}
+
+func TestRuleRegistry(t *testing.T) {
+	registry := helpers.NewRuleRegistry[string]("Required")
+	factory := func(args []string) (helpers.XValidation[string], error) {
+		return helpers.XValidation[string]{F: func(val string) bool { return val != "" }}, nil
+	}
+
+	if err := registry.Register("SKU", factory); err != nil {
+		t.Errorf("Register(SKU) -> %v; want nil", err)
+	}
+
+	for _, name := range []string{"SKU", "Required"} {
+		if err := registry.Register(name, factory); !errors.Is(err, helpers.ErrRuleExists) {
+			t.Errorf("Register(%s) -> %v; want ErrRuleExists", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "Bad=Name", "a b"} {
+		if err := registry.Register(name, factory); err == nil {
+			t.Errorf("Register(%q) -> nil; want error", name)
+		}
+	}
+
+	if err := registry.Register("Nil", nil); err == nil {
+		t.Errorf("Register(Nil, nil) -> nil; want error")
+	}
+
+	validation, err := registry.Build("SKU", nil)
+
+	if err != nil || validation.E.Rule != "SKU" || validation.E.Message != "is invalid" {
+		t.Errorf("Build(SKU) -> %+v, %v; want SKU rule with default message", validation.E, err)
+	}
+
+	if _, err := registry.Build("Unknown", nil); err != helpers.ErrUnknownRule {
+		t.Errorf("Build(Unknown) -> %v; want ErrUnknownRule", err)
+	}
+}
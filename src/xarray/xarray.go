@@ -0,0 +1,355 @@
+package xarray
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
+type XArray struct {
+	optional    bool
+	nullable    bool
+	required    bool
+	element     helpers.XObject
+	validations []helpers.XValidation[[]interface{}]
+}
+
+func Create() XArray {
+	xa := XArray{}
+	xa.validations = make([]helpers.XValidation[[]interface{}], 0)
+	return xa
+}
+
+var registry = helpers.NewRuleRegistry[[]interface{}]("Optional", "Nullable", "Required", "When", "Items", "MaxDepth", "MinItems", "MaxItems", "Length", "Unique", "Contains")
+
+func RegisterRule(name string, factory helpers.RuleFactory[[]interface{}]) error {
+	return registry.Register(name, factory)
+}
+
+func FromTags(validationTags []string) (XArray, error) {
+	rules, err := helpers.ParseTags(validationTags)
+
+	if err != nil {
+		return Create(), err
+	}
+
+	return FromRules(rules)
+}
+
+func FromRules(rules []xtag.Rule) (XArray, error) {
+	xa := Create()
+
+	for _, r := range rules {
+		rule, args := r.Name, r.Args
+
+		var err error
+
+		switch rule {
+		case "Optional":
+			xa, err = xa.Optional(), helpers.NoTagArgs(args)
+		case "Nullable":
+			xa, err = xa.Nullable(), helpers.NoTagArgs(args)
+		case "Required":
+			xa, err = xa.Required(), helpers.NoTagArgs(args)
+		case "MinItems":
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xa = xa.MinItems(n)
+		case "MaxItems":
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xa = xa.MaxItems(n)
+		case "Length":
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xa = xa.Length(n)
+		case "Unique":
+			xa, err = xa.Unique(), helpers.NoTagArgs(args)
+		case "Contains":
+			var arg string
+			var item interface{}
+
+			if arg, err = helpers.StringTagArg(args); err == nil {
+				if json.Unmarshal([]byte(arg), &item) != nil {
+					item = arg
+				} else if _, ok := item.(float64); ok {
+					item = json.Number(arg)
+				}
+
+				xa = xa.Contains(item)
+			}
+		default:
+			var validation helpers.XValidation[[]interface{}]
+
+			if validation, err = registry.Build(rule, args); err == nil {
+				xa = xa.withValidation(validation)
+			}
+		}
+
+		if err != nil {
+			return xa, &helpers.TagError{Rule: rule, Arg: strings.Join(args, ","), Err: err}
+		}
+	}
+
+	return xa, nil
+}
+
+func MustFromTags(validationTags []string) XArray {
+	xa, err := FromTags(validationTags)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return xa
+}
+
+func (xa XArray) addValidation(ruleName string, params map[string]interface{}, message string, validation func([]interface{}) bool) XArray {
+	return xa.withValidation(helpers.XValidation[[]interface{}]{E: helpers.NewError(ruleName, params, message), F: validation})
+}
+
+func (xa XArray) withValidation(validation helpers.XValidation[[]interface{}]) XArray {
+	validations := make([]helpers.XValidation[[]interface{}], len(xa.validations), len(xa.validations)+1)
+	copy(validations, xa.validations)
+
+	xa.validations = append(validations, validation)
+	return xa
+}
+
+func (xa XArray) Of(element helpers.XObject) XArray {
+	xa.element = element
+	return xa
+}
+
+func (xa XArray) Validate(val interface{}) (bool, []error) {
+	return xa.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (xa XArray) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	validationErrors := make([]error, 0)
+
+	val = helpers.Indirect(val)
+
+	if val == nil {
+		if xa.nullable {
+			return true, validationErrors
+		}
+
+		validationErrors = append(validationErrors, helpers.NullError())
+		return false, validationErrors
+	}
+
+	rv := reflect.ValueOf(val)
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		validationErrors = append(validationErrors, helpers.TypeError("array", val))
+		return false, validationErrors
+	}
+
+	value := make([]interface{}, rv.Len())
+
+	for i := range value {
+		value[i] = rv.Index(i).Interface()
+	}
+
+	for _, validation := range xa.validations {
+		if err := validation.Check(value, val); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	if xa.element != nil {
+		for i, item := range value {
+			if isValid, errs := helpers.ValidateContext(xa.element, item, ctx); !isValid {
+				for _, err := range errs {
+					validationErrors = append(validationErrors, helpers.WithPath(fmt.Sprintf("[%d]", i), item, err))
+				}
+			}
+		}
+	}
+
+	return len(validationErrors) == 0, validationErrors
+}
+
+func (xa XArray) String() string {
+	out := "XArray("
+
+	if xa.optional {
+		out += "Optional,"
+	}
+
+	if xa.nullable {
+		out += "Nullable,"
+	}
+
+	if xa.required {
+		out += "Required,"
+	}
+
+	if xa.element != nil {
+		out += "Of:" + xa.element.String() + ","
+	}
+
+	for _, validation := range xa.validations {
+		out += validation.E.Rule + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func (xa XArray) Optional() XArray {
+	xa.optional = true
+	return xa
+}
+
+func (xa XArray) Nullable() XArray {
+	xa.nullable = true
+	return xa
+}
+
+func (xa XArray) IsOptional() bool {
+	return xa.optional
+}
+
+func (xa XArray) IsNullable() bool {
+	return xa.nullable
+}
+
+func (xa XArray) Required() XArray {
+	xa.required = true
+	return xa
+}
+
+func (xa XArray) IsRequired() bool {
+	return xa.required
+}
+
+func (xa XArray) MinItems(minItems int, errorMessage ...string) XArray {
+	return xa.addValidation(
+		"MinItems",
+		map[string]interface{}{"min": minItems},
+		append(errorMessage, fmt.Sprintf("must contain at least: %v items", minItems))[0],
+		func(value []interface{}) bool {
+			return len(value) >= minItems
+		})
+}
+
+func (xa XArray) MaxItems(maxItems int, errorMessage ...string) XArray {
+	return xa.addValidation(
+		"MaxItems",
+		map[string]interface{}{"max": maxItems},
+		append(errorMessage, fmt.Sprintf("must contain at most: %v items", maxItems))[0],
+		func(value []interface{}) bool {
+			return len(value) <= maxItems
+		})
+}
+
+func (xa XArray) Length(length int, errorMessage ...string) XArray {
+	return xa.addValidation(
+		"Length",
+		map[string]interface{}{"length": length},
+		append(errorMessage, fmt.Sprintf("must contain exactly: %v items", length))[0],
+		func(value []interface{}) bool {
+			return len(value) == length
+		})
+}
+
+func (xa XArray) Unique(errorMessage ...string) XArray {
+	return xa.addValidation(
+		"Unique",
+		nil,
+		append(errorMessage, "must contain unique items")[0],
+		func(value []interface{}) bool {
+			for i := range value {
+				for j := i + 1; j < len(value); j++ {
+					if equal(value[i], value[j]) {
+						return false
+					}
+				}
+			}
+			return true
+		})
+}
+
+func (xa XArray) Contains(item interface{}, errorMessage ...string) XArray {
+	return xa.addValidation(
+		"Contains",
+		map[string]interface{}{"item": item},
+		append(errorMessage, fmt.Sprintf("must contain: %v", item))[0],
+		func(value []interface{}) bool {
+			for _, v := range value {
+				if equal(v, item) {
+					return true
+				}
+			}
+			return false
+		})
+}
+
+func equal(a interface{}, b interface{}) bool {
+	if ai, ok := toInteger(a); ok {
+		if bi, ok := toInteger(b); ok {
+			return ai.Cmp(bi) == 0
+		}
+	}
+
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	if val == nil {
+		return 0, false
+	}
+
+	if number, ok := val.(json.Number); ok {
+		value, err := number.Float64()
+		return value, err == nil
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
+
+func toInteger(val interface{}) (*big.Int, bool) {
+	if val == nil {
+		return nil, false
+	}
+
+	if number, ok := val.(json.Number); ok {
+		return new(big.Int).SetString(string(number), 10)
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), true
+	}
+
+	return nil, false
+}
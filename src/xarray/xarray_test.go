@@ -0,0 +1,230 @@
+package xarray_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xarray"
+	"github.com/radchukd/go-xschema/src/xstring"
+)
+
+func TestValidate(t *testing.T) {
+	xa := xarray.Create()
+
+	value := []string{"a", "b"}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("Validate(%v) -> false; want true", value)
+	}
+
+	jsonValue := []interface{}{"a", 1.0}
+
+	if isValid, _ := xa.Validate(jsonValue); !isValid {
+		t.Errorf("Validate(%v) -> false; want true", jsonValue)
+	}
+
+	strValue := "a"
+
+	if isValid, _ := xa.Validate(strValue); isValid {
+		t.Errorf("Validate(%s) -> true; want false", strValue)
+	}
+}
+
+func TestCustomMessage(t *testing.T) {
+	value := []string{}
+	msg := "req"
+	xa := xarray.Create().MinItems(1, msg)
+
+	if _, err := xa.Validate(value); fmt.Sprint(err[0]) != msg {
+		t.Errorf("MinItems(%v, %s) -> \"must contain at least: 1 items\"; want %s", value, msg, msg)
+	}
+}
+
+func TestOf(t *testing.T) {
+	var value []string
+	xa := xarray.Create().Of(xstring.Create().Min(2))
+
+	value = []string{"ab", "c", "de"}
+
+	isValid, errs := xa.Validate(value)
+
+	if isValid {
+		t.Errorf("Of(%v) -> true; want false", value)
+	}
+
+	if pe, ok := errs[0].(*helpers.ValidationError); !ok || pe.Path != "[1]" {
+		t.Errorf("Of(%v) -> %v; want error at [1]", value, errs)
+	}
+
+	value = []string{"ab", "cd"}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("Of(%v) -> false; want true", value)
+	}
+}
+
+func TestMinItems(t *testing.T) {
+	var value []int
+	n := 2
+	xa := xarray.Create().MinItems(n)
+
+	value = []int{1}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("MinItems(%v,%v) -> true; want false", n, value)
+	}
+
+	value = []int{1, 2}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("MinItems(%v,%v) -> false; want true", n, value)
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	var value []int
+	n := 2
+	xa := xarray.Create().MaxItems(n)
+
+	value = []int{1, 2, 3}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("MaxItems(%v,%v) -> true; want false", n, value)
+	}
+
+	value = []int{1, 2}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("MaxItems(%v,%v) -> false; want true", n, value)
+	}
+}
+
+func TestLength(t *testing.T) {
+	var value []int
+	n := 2
+	xa := xarray.Create().Length(n)
+
+	value = []int{1}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("Length(%v,%v) -> true; want false", n, value)
+	}
+
+	value = []int{1, 2}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("Length(%v,%v) -> false; want true", n, value)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	var value []interface{}
+	xa := xarray.Create().Unique()
+
+	value = []interface{}{1, 2, 1.0}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("Unique(%v) -> true; want false", value)
+	}
+
+	value = []interface{}{1, 2, "1"}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("Unique(%v) -> false; want true", value)
+	}
+
+	exact := []int64{9007199254740993, 9007199254740992}
+
+	if isValid, _ := xa.Validate(exact); !isValid {
+		t.Errorf("Unique(%v) -> false; want true", exact)
+	}
+
+	value = []interface{}{json.Number("18446744073709551615"), uint64(18446744073709551615)}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("Unique(%v) -> true; want false", value)
+	}
+}
+
+func TestContains(t *testing.T) {
+	var value []interface{}
+	item := 3
+	xa := xarray.Create().Contains(item)
+
+	value = []interface{}{1, 2}
+
+	if isValid, _ := xa.Validate(value); isValid {
+		t.Errorf("Contains(%v,%v) -> true; want false", item, value)
+	}
+
+	value = []interface{}{1, 3.0}
+
+	if isValid, _ := xa.Validate(value); !isValid {
+		t.Errorf("Contains(%v,%v) -> false; want true", item, value)
+	}
+
+	xa = xarray.MustFromTags([]string{"Contains=9007199254740993"})
+	ids := []int64{9007199254740992}
+
+	if isValid, _ := xa.Validate(ids); isValid {
+		t.Errorf("Contains(9007199254740993,%v) -> true; want false", ids)
+	}
+
+	ids = append(ids, 9007199254740993)
+
+	if isValid, _ := xa.Validate(ids); !isValid {
+		t.Errorf("Contains(9007199254740993,%v) -> false; want true", ids)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	xa := xarray.Create()
+
+	if isValid, _ := xa.Validate(nil); isValid {
+		t.Errorf("Validate(nil) -> true; want false")
+	}
+
+	xa = xa.Nullable()
+
+	if isValid, _ := xa.Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+
+	if isValid, _ := xa.Validate([]int{1}); !isValid {
+		t.Errorf("Nullable(%v) -> false; want true", []int{1})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	xa := xarray.Create()
+
+	if xa.IsRequired() {
+		t.Errorf("IsRequired() -> true; want false")
+	}
+
+	xa = xarray.MustFromTags([]string{"Required"})
+
+	if !xa.IsRequired() || xa.String() != "XArray(Required,)" {
+		t.Errorf("Required() -> %s; want XArray(Required,)", xa.String())
+	}
+
+	if isValid, _ := xa.Validate([]int{}); !isValid {
+		t.Errorf("Required([]) -> false; want true")
+	}
+}
+
+func TestFromTags(t *testing.T) {
+	xa, err := xarray.FromTags([]string{"MinItems=1", "Unique", "Contains=admin"})
+
+	if err != nil || xa.String() != "XArray(MinItems,Unique,Contains,)" {
+		t.Errorf("FromTags() -> %s, %v; want XArray(MinItems,Unique,Contains,)", xa.String(), err)
+	}
+
+	for _, tag := range []string{"MinItems=abc", "Unique=1", "MinLength=1"} {
+		if _, err := xarray.FromTags([]string{tag}); err == nil {
+			t.Errorf("FromTags(%s) -> nil; want error", tag)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package xbool
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
+type XBool struct {
+	optional    bool
+	nullable    bool
+	validations []helpers.XValidation[bool]
+}
+
+func Create() XBool {
+	xb := XBool{}
+	xb.validations = make([]helpers.XValidation[bool], 0)
+	return xb
+}
+
+var registry = helpers.NewRuleRegistry[bool]("Optional", "Nullable", "Required", "When", "Items", "MaxDepth", "MustBe")
+
+func RegisterRule(name string, factory helpers.RuleFactory[bool]) error {
+	return registry.Register(name, factory)
+}
+
+func FromTags(validationTags []string) (XBool, error) {
+	rules, err := helpers.ParseTags(validationTags)
+
+	if err != nil {
+		return Create(), err
+	}
+
+	return FromRules(rules)
+}
+
+func FromRules(rules []xtag.Rule) (XBool, error) {
+	xb := Create()
+
+	for _, r := range rules {
+		rule, args := r.Name, r.Args
+
+		var err error
+
+		switch rule {
+		case "Optional":
+			xb, err = xb.Optional(), helpers.NoTagArgs(args)
+		case "Nullable":
+			xb, err = xb.Nullable(), helpers.NoTagArgs(args)
+		case "Required":
+			xb, err = xb.Required(), helpers.NoTagArgs(args)
+		case "MustBe":
+			var n bool
+			n, err = helpers.BoolTagArg(args)
+			xb = xb.MustBe(n)
+		default:
+			var validation helpers.XValidation[bool]
+
+			if validation, err = registry.Build(rule, args); err == nil {
+				xb = xb.withValidation(validation)
+			}
+		}
+
+		if err != nil {
+			return xb, &helpers.TagError{Rule: rule, Arg: strings.Join(args, ","), Err: err}
+		}
+	}
+
+	return xb, nil
+}
+
+func MustFromTags(validationTags []string) XBool {
+	xb, err := FromTags(validationTags)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return xb
+}
+
+func (xb XBool) addValidation(ruleName string, params map[string]interface{}, message string, validation func(bool) bool) XBool {
+	return xb.withValidation(helpers.XValidation[bool]{E: helpers.NewError(ruleName, params, message), F: validation})
+}
+
+func (xb XBool) withValidation(validation helpers.XValidation[bool]) XBool {
+	validations := make([]helpers.XValidation[bool], len(xb.validations), len(xb.validations)+1)
+	copy(validations, xb.validations)
+
+	xb.validations = append(validations, validation)
+	return xb
+}
+
+func (xb XBool) Validate(val interface{}) (bool, []error) {
+	validationErrors := make([]error, 0)
+
+	val = helpers.Indirect(val)
+
+	if val == nil {
+		if xb.nullable {
+			return true, validationErrors
+		}
+
+		validationErrors = append(validationErrors, helpers.NullError())
+		return false, validationErrors
+	}
+
+	if reflect.TypeOf(val).Kind() != reflect.Bool {
+		validationErrors = append(validationErrors, helpers.TypeError("bool", val))
+		return false, validationErrors
+	}
+
+	value := reflect.ValueOf(val).Bool()
+
+	for _, validation := range xb.validations {
+		if err := validation.Check(value, val); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	return len(validationErrors) == 0, validationErrors
+}
+
+func (xb XBool) IsRequired() bool {
+	for _, validation := range xb.validations {
+		if validation.E.Rule == "Required" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (xb XBool) String() string {
+	out := "XBool("
+
+	if xb.optional {
+		out += "Optional,"
+	}
+
+	if xb.nullable {
+		out += "Nullable,"
+	}
+
+	for _, validation := range xb.validations {
+		out += validation.E.Rule + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func (xb XBool) Optional() XBool {
+	xb.optional = true
+	return xb
+}
+
+func (xb XBool) Nullable() XBool {
+	xb.nullable = true
+	return xb
+}
+
+func (xb XBool) IsOptional() bool {
+	return xb.optional
+}
+
+func (xb XBool) IsNullable() bool {
+	return xb.nullable
+}
+
+func (xb XBool) Required(errorMessage ...string) XBool {
+	return xb.addValidation(
+		"Required",
+		nil,
+		append(errorMessage, "must be true")[0],
+		func(value bool) bool {
+			return value
+		})
+}
+
+func (xb XBool) MustBe(expected bool, errorMessage ...string) XBool {
+	return xb.addValidation(
+		"MustBe",
+		map[string]interface{}{"expected": expected},
+		append(errorMessage, fmt.Sprintf("must be: %v", expected))[0],
+		func(value bool) bool {
+			return value == expected
+		})
+}
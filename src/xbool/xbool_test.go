@@ -0,0 +1,101 @@
+package xbool_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/radchukd/go-xschema/src/xbool"
+)
+
+func TestValidate(t *testing.T) {
+	xb := xbool.Create()
+
+	value := false
+
+	if isValid, _ := xb.Validate(value); !isValid {
+		t.Errorf("Validate(%v) -> false; want true", value)
+	}
+
+	intValue := 1
+
+	if isValid, _ := xb.Validate(intValue); isValid {
+		t.Errorf("Validate(%v) -> true; want false", intValue)
+	}
+
+	if isValid, _ := xb.Validate(nil); isValid {
+		t.Errorf("Validate(nil) -> true; want false")
+	}
+}
+
+func TestRequired(t *testing.T) {
+	var value bool
+	xb := xbool.Create().Required()
+
+	value = true
+
+	if isValid, _ := xb.Validate(value); !isValid {
+		t.Errorf("Required(%v) -> false; want true", value)
+	}
+
+	value = false
+
+	if isValid, _ := xb.Validate(value); isValid {
+		t.Errorf("Required(%v) -> true; want false", value)
+	}
+}
+
+func TestCustomMessage(t *testing.T) {
+	value := false
+	msg := "req"
+	xb := xbool.Create().Required(msg)
+
+	if _, err := xb.Validate(value); fmt.Sprint(err[0]) != msg {
+		t.Errorf("Required(%v, %s) -> \"must be true\"; want %s", value, msg, msg)
+	}
+}
+
+func TestMustBe(t *testing.T) {
+	var value bool
+	expected := false
+	xb := xbool.Create().MustBe(expected)
+
+	value = true
+
+	if isValid, _ := xb.Validate(value); isValid {
+		t.Errorf("MustBe(%v,%v) -> true; want false", expected, value)
+	}
+
+	value = false
+
+	if isValid, _ := xb.Validate(value); !isValid {
+		t.Errorf("MustBe(%v,%v) -> false; want true", expected, value)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	xb := xbool.Create().Required().Nullable()
+
+	if isValid, _ := xb.Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+
+	value := false
+
+	if isValid, _ := xb.Validate(value); isValid {
+		t.Errorf("Nullable(%v) -> true; want false", value)
+	}
+}
+
+func TestFromTags(t *testing.T) {
+	xb, err := xbool.FromTags([]string{"Nullable", "MustBe=true"})
+
+	if err != nil || xb.String() != "XBool(Nullable,MustBe,)" {
+		t.Errorf("FromTags() -> %s, %v; want XBool(Nullable,MustBe,)", xb.String(), err)
+	}
+
+	for _, tag := range []string{"MustBe=maybe", "MustBe", "True"} {
+		if _, err := xbool.FromTags([]string{tag}); err == nil {
+			t.Errorf("FromTags(%s) -> nil; want error", tag)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package xfloat
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
+type XFloat struct {
+	optional    bool
+	nullable    bool
+	validations []helpers.XValidation[float64]
+}
+
+func Create() XFloat {
+	xf := XFloat{}
+	xf.validations = make([]helpers.XValidation[float64], 0)
+	return xf
+}
+
+var registry = helpers.NewRuleRegistry[float64]("Optional", "Nullable", "Required", "When", "Items", "MaxDepth", "Gt", "Gte", "Lt", "Lte", "MultipleOf", "OneOf", "Finite", "NotNaN")
+
+func RegisterRule(name string, factory helpers.RuleFactory[float64]) error {
+	return registry.Register(name, factory)
+}
+
+func FromTags(validationTags []string) (XFloat, error) {
+	rules, err := helpers.ParseTags(validationTags)
+
+	if err != nil {
+		return Create(), err
+	}
+
+	return FromRules(rules)
+}
+
+func FromRules(rules []xtag.Rule) (XFloat, error) {
+	xf := Create()
+
+	for _, r := range rules {
+		rule, args := r.Name, r.Args
+
+		var err error
+
+		switch rule {
+		case "Optional":
+			xf, err = xf.Optional(), helpers.NoTagArgs(args)
+		case "Nullable":
+			xf, err = xf.Nullable(), helpers.NoTagArgs(args)
+		case "Required":
+			xf, err = xf.Required(), helpers.NoTagArgs(args)
+		case "Gt":
+			var n float64
+			n, err = helpers.FloatTagArg(args)
+			xf = xf.Gt(n)
+		case "Gte":
+			var n float64
+			n, err = helpers.FloatTagArg(args)
+			xf = xf.Gte(n)
+		case "Lt":
+			var n float64
+			n, err = helpers.FloatTagArg(args)
+			xf = xf.Lt(n)
+		case "Lte":
+			var n float64
+			n, err = helpers.FloatTagArg(args)
+			xf = xf.Lte(n)
+		case "MultipleOf":
+			var n float64
+			n, err = helpers.FloatTagArg(args)
+			xf = xf.MultipleOf(n)
+		case "OneOf":
+			var values []float64
+			values, err = helpers.ListTagArg(args, parseFloat)
+			xf = xf.OneOf(values)
+		case "Finite":
+			xf, err = xf.Finite(), helpers.NoTagArgs(args)
+		case "NotNaN":
+			xf, err = xf.NotNaN(), helpers.NoTagArgs(args)
+		default:
+			var validation helpers.XValidation[float64]
+
+			if validation, err = registry.Build(rule, args); err == nil {
+				xf = xf.withValidation(validation)
+			}
+		}
+
+		if err != nil {
+			return xf, &helpers.TagError{Rule: rule, Arg: strings.Join(args, ","), Err: err}
+		}
+	}
+
+	return xf, nil
+}
+
+func MustFromTags(validationTags []string) XFloat {
+	xf, err := FromTags(validationTags)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return xf
+}
+
+func (xf XFloat) addValidation(ruleName string, params map[string]interface{}, message string, validation func(float64) bool) XFloat {
+	return xf.withValidation(helpers.XValidation[float64]{E: helpers.NewError(ruleName, params, message), F: validation})
+}
+
+func (xf XFloat) withValidation(validation helpers.XValidation[float64]) XFloat {
+	validations := make([]helpers.XValidation[float64], len(xf.validations), len(xf.validations)+1)
+	copy(validations, xf.validations)
+
+	xf.validations = append(validations, validation)
+	return xf
+}
+
+func (xf XFloat) Validate(val interface{}) (bool, []error) {
+	validationErrors := make([]error, 0)
+
+	val = helpers.Indirect(val)
+
+	if val == nil {
+		if xf.nullable {
+			return true, validationErrors
+		}
+
+		validationErrors = append(validationErrors, helpers.NullError())
+		return false, validationErrors
+	}
+
+	value, ok := toFloat(val)
+
+	if !ok {
+		validationErrors = append(validationErrors, helpers.TypeError("number", val))
+		return false, validationErrors
+	}
+
+	for _, validation := range xf.validations {
+		if err := validation.Check(value, val); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	return len(validationErrors) == 0, validationErrors
+}
+
+func (xf XFloat) IsRequired() bool {
+	for _, validation := range xf.validations {
+		if validation.E.Rule == "Required" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (xf XFloat) String() string {
+	out := "XFloat("
+
+	if xf.optional {
+		out += "Optional,"
+	}
+
+	if xf.nullable {
+		out += "Nullable,"
+	}
+
+	for _, validation := range xf.validations {
+		out += validation.E.Rule + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func (xf XFloat) Optional() XFloat {
+	xf.optional = true
+	return xf
+}
+
+func (xf XFloat) Nullable() XFloat {
+	xf.nullable = true
+	return xf
+}
+
+func (xf XFloat) IsOptional() bool {
+	return xf.optional
+}
+
+func (xf XFloat) IsNullable() bool {
+	return xf.nullable
+}
+
+func (xf XFloat) Required(errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Required",
+		nil,
+		append(errorMessage, "must be non-zero")[0],
+		func(value float64) bool {
+			return value != 0
+		})
+}
+
+func (xf XFloat) Gt(gtValue float64, errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Gt",
+		map[string]interface{}{"gt": gtValue},
+		append(errorMessage, fmt.Sprintf("must be greater than: %v", gtValue))[0],
+		func(value float64) bool {
+			return value > gtValue
+		})
+}
+
+func (xf XFloat) Gte(gteValue float64, errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Gte",
+		map[string]interface{}{"gte": gteValue},
+		append(errorMessage, fmt.Sprintf("must be greater or equal to: %v", gteValue))[0],
+		func(value float64) bool {
+			return value >= gteValue
+		})
+}
+
+func (xf XFloat) Lt(ltValue float64, errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Lt",
+		map[string]interface{}{"lt": ltValue},
+		append(errorMessage, fmt.Sprintf("must be lesser than: %v", ltValue))[0],
+		func(value float64) bool {
+			return value < ltValue
+		})
+}
+
+func (xf XFloat) Lte(lteValue float64, errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Lte",
+		map[string]interface{}{"lte": lteValue},
+		append(errorMessage, fmt.Sprintf("must be lesser or equal to: %v", lteValue))[0],
+		func(value float64) bool {
+			return value <= lteValue
+		})
+}
+
+func (xf XFloat) MultipleOf(mtValue float64, errorMessage ...string) XFloat {
+	step, isStep := toRat(mtValue)
+
+	return xf.addValidation(
+		"MultipleOf",
+		map[string]interface{}{"multipleOf": mtValue},
+		append(errorMessage, fmt.Sprintf("must be a multiple of: %v", mtValue))[0],
+		func(value float64) bool {
+			if !isStep || step.Sign() == 0 {
+				return false
+			}
+
+			rat, ok := toRat(value)
+
+			if !ok {
+				return false
+			}
+
+			return rat.Quo(rat, step).IsInt()
+		})
+}
+
+func (xf XFloat) OneOf(possibleValues []float64, errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"OneOf",
+		map[string]interface{}{"values": possibleValues},
+		append(errorMessage, fmt.Sprintf("must be one of: %v", possibleValues))[0],
+		func(value float64) bool {
+			for _, v := range possibleValues {
+				if value == v {
+					return true
+				}
+			}
+			return false
+		})
+}
+
+func (xf XFloat) Finite(errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"Finite",
+		nil,
+		append(errorMessage, "must be finite")[0],
+		func(value float64) bool {
+			return !math.IsNaN(value) && !math.IsInf(value, 0)
+		})
+}
+
+func (xf XFloat) NotNaN(errorMessage ...string) XFloat {
+	return xf.addValidation(
+		"NotNaN",
+		nil,
+		append(errorMessage, "must be a number")[0],
+		func(value float64) bool {
+			return !math.IsNaN(value)
+		})
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	if val == nil {
+		return 0, false
+	}
+
+	if number, ok := val.(json.Number); ok {
+		value, err := number.Float64()
+		return value, err == nil
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32:
+		// Widen through the shortest decimal form so float32(17.9) compares as 17.9.
+		value, err := strconv.ParseFloat(strconv.FormatFloat(rv.Float(), 'g', -1, 32), 64)
+		return value, err == nil
+	case reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
+
+// Both sides go through their shortest decimal form so that a step such as
+// 0.1 divides 0.3 exactly instead of failing on binary rounding.
+func toRat(value float64) (*big.Rat, bool) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, false
+	}
+
+	return new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func parseFloat(arg string) (float64, error) {
+	return strconv.ParseFloat(arg, 64)
+}
@@ -0,0 +1,265 @@
+package xfloat_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/radchukd/go-xschema/src/xfloat"
+)
+
+func TestValidate(t *testing.T) {
+	xf := xfloat.Create()
+
+	floatValue := 12.5
+
+	if isValid, _ := xf.Validate(floatValue); !isValid {
+		t.Errorf("Validate(%v) -> false; want true", floatValue)
+	}
+
+	intValue := 12
+
+	if isValid, _ := xf.Validate(intValue); !isValid {
+		t.Errorf("Validate(%v) -> false; want true", intValue)
+	}
+
+	value := ""
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Validate(%s) -> true; want false", value)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	var value float64
+	xf := xfloat.Create().Required()
+
+	value = 0.1
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Required(%v) -> false; want true", value)
+	}
+
+	value = 0
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Required(%v) -> true; want false", value)
+	}
+}
+
+func TestCustomMessage(t *testing.T) {
+	value := 0.0
+	msg := "req"
+	xf := xfloat.Create().Required(msg)
+
+	if _, err := xf.Validate(value); fmt.Sprint(err[0]) != msg {
+		t.Errorf("Required(%v, %s) -> \"must be non-zero\"; want %s", value, msg, msg)
+	}
+}
+
+func TestGt(t *testing.T) {
+	var value float64
+	compVal := 17.0
+	xf := xfloat.Create().Gt(compVal)
+
+	value = 17
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Gt(%v,%v) -> true; want false", compVal, value)
+	}
+
+	value = 17.9
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Gt(%v,%v) -> false; want true", compVal, value)
+	}
+}
+
+func TestGte(t *testing.T) {
+	var value float64
+	compVal := 17.5
+	xf := xfloat.Create().Gte(compVal)
+
+	value = 17.4
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Gte(%v,%v) -> true; want false", compVal, value)
+	}
+
+	value = 17.5
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Gte(%v,%v) -> false; want true", compVal, value)
+	}
+}
+
+func TestLt(t *testing.T) {
+	var value float64
+	compVal := 18.0
+	xf := xfloat.Create().Lt(compVal)
+
+	value = 18
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Lt(%v,%v) -> true; want false", compVal, value)
+	}
+
+	value = 17.9
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Lt(%v,%v) -> false; want true", compVal, value)
+	}
+}
+
+func TestLte(t *testing.T) {
+	var value float64
+	compVal := 17.5
+	xf := xfloat.Create().Lte(compVal)
+
+	value = 17.6
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Lte(%v,%v) -> true; want false", compVal, value)
+	}
+
+	value = 17.5
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Lte(%v,%v) -> false; want true", compVal, value)
+	}
+}
+
+func TestMultipleOf(t *testing.T) {
+	var value float64
+	mValue := 0.1
+	xf := xfloat.Create().MultipleOf(mValue)
+
+	value = 0.35
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("MultipleOf(%v,%v) -> true; want false", mValue, value)
+	}
+
+	value = 0.3
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("MultipleOf(%v,%v) -> false; want true", mValue, value)
+	}
+
+	cases := []struct {
+		step  float64
+		value float64
+		want  bool
+	}{
+		{1, 1e9 + 0.5, false},
+		{0.01, 12345678.005, false},
+		{0.01, 12345678.01, true},
+		{0.5, -2.5, true},
+		{3, 1e300, false},
+		{0, 0, false},
+		{1, math.Inf(1), false},
+	}
+
+	for _, c := range cases {
+		if isValid, _ := xfloat.Create().MultipleOf(c.step).Validate(c.value); isValid != c.want {
+			t.Errorf("MultipleOf(%v,%v) -> %v; want %v", c.step, c.value, isValid, c.want)
+		}
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	var value float64
+	possibleValues := []float64{0.5, 1.5}
+	xf := xfloat.Create().OneOf(possibleValues)
+
+	value = 1.5
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("OneOf(%v,%v) -> false; want true", possibleValues, value)
+	}
+
+	value = 1
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("OneOf(%v,%v) -> true; want false", possibleValues, value)
+	}
+}
+
+func TestFinite(t *testing.T) {
+	var value float64
+	xf := xfloat.Create().Finite()
+
+	value = math.Inf(1)
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("Finite(%v) -> true; want false", value)
+	}
+
+	value = 1e308
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("Finite(%v) -> false; want true", value)
+	}
+}
+
+func TestNotNaN(t *testing.T) {
+	var value float64
+	xf := xfloat.Create().NotNaN()
+
+	value = math.NaN()
+
+	if isValid, _ := xf.Validate(value); isValid {
+		t.Errorf("NotNaN(%v) -> true; want false", value)
+	}
+
+	value = math.Inf(-1)
+
+	if isValid, _ := xf.Validate(value); !isValid {
+		t.Errorf("NotNaN(%v) -> false; want true", value)
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	value := float32(17.9)
+	xf := xfloat.Create().Gte(17.9).Lte(17.9).OneOf([]float64{17.9})
+
+	if isValid, errs := xf.Validate(value); !isValid {
+		t.Errorf("Validate(float32(%v)) -> %v; want true", value, errs)
+	}
+
+	if isValid, _ := xfloat.Create().Gt(17.9).Validate(value); isValid {
+		t.Errorf("Gt(17.9, float32(%v)) -> true; want false", value)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	xf := xfloat.Create()
+
+	if isValid, _ := xf.Validate(nil); isValid {
+		t.Errorf("Validate(nil) -> true; want false")
+	}
+
+	xf = xf.Nullable()
+
+	if isValid, _ := xf.Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+
+	if isValid, _ := xf.Validate(1.5); !isValid {
+		t.Errorf("Nullable(%v) -> false; want true", 1.5)
+	}
+}
+
+func TestFromTags(t *testing.T) {
+	xf, err := xfloat.FromTags([]string{"Gt=0.5", "Finite"})
+
+	if err != nil || xf.String() != "XFloat(Gt,Finite,)" {
+		t.Errorf("FromTags() -> %s, %v; want XFloat(Gt,Finite,)", xf.String(), err)
+	}
+
+	for _, tag := range []string{"Gt=abc", "Finite=1", "Infinite"} {
+		if _, err := xfloat.FromTags([]string{tag}); err == nil {
+			t.Errorf("FromTags(%s) -> nil; want error", tag)
+		}
+	}
+}
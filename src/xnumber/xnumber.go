@@ -4,92 +4,180 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xtag"
 )
 
 type XNumber struct {
-	validations map[string]helpers.XValidation[int]
+	optional    bool
+	nullable    bool
+	validations []helpers.XValidation[*big.Int]
 }
 
 func Create() XNumber {
 	xn := XNumber{}
-	xn.validations = make(map[string]helpers.XValidation[int])
+	xn.validations = make([]helpers.XValidation[*big.Int], 0)
 	return xn
 }
 
-func FromTags(validationTags []string) XNumber {
+var registry = helpers.NewRuleRegistry[*big.Int]("Optional", "Nullable", "Required", "When", "Items", "MaxDepth", "Gt", "Gte", "Lt", "Lte", "MultipleOf", "OneOf")
+
+func RegisterRule(name string, factory helpers.RuleFactory[*big.Int]) error {
+	return registry.Register(name, factory)
+}
+
+func FromTags(validationTags []string) (XNumber, error) {
+	rules, err := helpers.ParseTags(validationTags)
+
+	if err != nil {
+		return Create(), err
+	}
+
+	return FromRules(rules)
+}
+
+func FromRules(rules []xtag.Rule) (XNumber, error) {
 	xn := Create()
 
-	for _, v := range validationTags {
-		nameArg := strings.Split(v, "=")
+	for _, r := range rules {
+		rule, args := r.Name, r.Args
 
-		switch nameArg[0] {
+		var err error
+
+		switch rule {
+		case "Optional":
+			xn, err = xn.Optional(), helpers.NoTagArgs(args)
+		case "Nullable":
+			xn, err = xn.Nullable(), helpers.NoTagArgs(args)
 		case "Required":
-			xn = xn.Required()
+			xn, err = xn.Required(), helpers.NoTagArgs(args)
 		case "Gt":
-			n, _ := strconv.Atoi(nameArg[1])
-			xn = xn.Gt(n)
+			var n *big.Int
+			if n, err = helpers.BigIntTagArg(args); err == nil {
+				xn = xn.GtBig(n)
+			}
 		case "Gte":
-			n, _ := strconv.Atoi(nameArg[1])
-			xn = xn.Gte(n)
+			var n *big.Int
+			if n, err = helpers.BigIntTagArg(args); err == nil {
+				xn = xn.GteBig(n)
+			}
 		case "Lt":
-			n, _ := strconv.Atoi(nameArg[1])
-			xn = xn.Lt(n)
+			var n *big.Int
+			if n, err = helpers.BigIntTagArg(args); err == nil {
+				xn = xn.LtBig(n)
+			}
 		case "Lte":
-			n, _ := strconv.Atoi(nameArg[1])
-			xn = xn.Lte(n)
+			var n *big.Int
+			if n, err = helpers.BigIntTagArg(args); err == nil {
+				xn = xn.LteBig(n)
+			}
 		case "MultipleOf":
-			n, _ := strconv.Atoi(nameArg[1])
-			xn = xn.MultipleOf(n)
+			var n *big.Int
+			if n, err = helpers.BigIntTagArg(args); err == nil {
+				xn = xn.MultipleOfBig(n)
+			}
 		case "OneOf":
-			var ooArr []int
-			json.Unmarshal([]byte(nameArg[1]), &ooArr)
-			xn = xn.OneOf(ooArr)
+			var values []*big.Int
+			if values, err = helpers.ListTagArg(args, helpers.ParseBigInt); err == nil {
+				xn = xn.OneOfBig(values)
+			}
+		default:
+			var validation helpers.XValidation[*big.Int]
+
+			if validation, err = registry.Build(rule, args); err == nil {
+				xn = xn.withValidation(isolate(validation))
+			}
+		}
+
+		if err != nil {
+			return xn, &helpers.TagError{Rule: rule, Arg: strings.Join(args, ","), Err: err}
 		}
 	}
 
+	return xn, nil
+}
+
+func MustFromTags(validationTags []string) XNumber {
+	xn, err := FromTags(validationTags)
+
+	if err != nil {
+		panic(err)
+	}
+
 	return xn
 }
 
-func (xn XNumber) addValidation(ruleName string, err error, validation func(int) bool) XNumber {
-	xn.validations[ruleName] = helpers.XValidation[int]{E: err, F: validation}
+func (xn XNumber) addValidation(ruleName string, params map[string]interface{}, message string, validation func(*big.Int) bool) XNumber {
+	return xn.withValidation(helpers.XValidation[*big.Int]{E: helpers.NewError(ruleName, params, message), F: validation})
+}
+
+func (xn XNumber) withValidation(validation helpers.XValidation[*big.Int]) XNumber {
+	validations := make([]helpers.XValidation[*big.Int], len(xn.validations), len(xn.validations)+1)
+	copy(validations, xn.validations)
+
+	xn.validations = append(validations, validation)
 	return xn
 }
 
 func (xn XNumber) Validate(val interface{}) (bool, []error) {
 	validationErrors := make([]error, 0)
 
-	if reflect.TypeOf(val).Kind() == reflect.Float32 {
-		return xn.Validate(int(val.(float32)))
-	} else if reflect.TypeOf(val).Kind() == reflect.Float64 {
-		return xn.Validate(int(val.(float64)))
-	} else if reflect.TypeOf(val).Kind() != reflect.Int {
-		validationErrors = append(validationErrors, fmt.Errorf("invalid type"))
+	val = helpers.Indirect(val)
+
+	if val == nil {
+		if xn.nullable {
+			return true, validationErrors
+		}
+
+		validationErrors = append(validationErrors, helpers.NullError())
 		return false, validationErrors
 	}
 
-	value := val.(int)
+	value, err := toInteger(val)
 
-	for _, validation := range xn.validations {
-		isValid := validation.F(value)
+	if err != nil {
+		validationErrors = append(validationErrors, err)
+		return false, validationErrors
+	}
 
-		if !isValid {
-			validationErrors = append(validationErrors, validation.E)
+	for _, validation := range xn.validations {
+		if err := validation.Check(value, val); err != nil {
+			validationErrors = append(validationErrors, err)
 		}
 	}
 
 	return len(validationErrors) == 0, validationErrors
 }
 
+func (xn XNumber) IsRequired() bool {
+	for _, validation := range xn.validations {
+		if validation.E.Rule == "Required" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (xn XNumber) String() string {
 	out := "XNumber("
 
-	for validationName := range xn.validations {
-		out += validationName + ","
+	if xn.optional {
+		out += "Optional,"
+	}
+
+	if xn.nullable {
+		out += "Nullable,"
+	}
+
+	for _, validation := range xn.validations {
+		out += validation.E.Rule + ","
 	}
 
 	out += ")"
@@ -97,70 +185,268 @@ func (xn XNumber) String() string {
 	return out
 }
 
+func (xn XNumber) Optional() XNumber {
+	xn.optional = true
+	return xn
+}
+
+func (xn XNumber) Nullable() XNumber {
+	xn.nullable = true
+	return xn
+}
+
+func (xn XNumber) IsOptional() bool {
+	return xn.optional
+}
+
+func (xn XNumber) IsNullable() bool {
+	return xn.nullable
+}
+
 func (xn XNumber) Required(errorMessage ...string) XNumber {
 	return xn.addValidation(
 		"Required",
-		errors.New(append(errorMessage, "must be non-zero")[0]),
-		func(value int) bool {
-			return value != 0
+		nil,
+		append(errorMessage, "must be non-zero")[0],
+		func(value *big.Int) bool {
+			return value.Sign() != 0
 		})
 }
 
 func (xn XNumber) Gt(gtValue int, errorMessage ...string) XNumber {
-	return xn.addValidation(
-		"Gt",
-		errors.New(append(errorMessage, fmt.Sprintf("must be greater than: %v", gtValue))[0]),
-		func(value int) bool {
-			return value > gtValue
-		})
+	return xn.compare("Gt", "gt", gtValue, big.NewInt(int64(gtValue)), "must be greater than: %v", errorMessage, func(cmp int) bool { return cmp > 0 })
+}
+
+func (xn XNumber) GtBig(gtValue *big.Int, errorMessage ...string) XNumber {
+	gtValue = new(big.Int).Set(gtValue)
+	return xn.compare("Gt", "gt", gtValue, gtValue, "must be greater than: %v", errorMessage, func(cmp int) bool { return cmp > 0 })
 }
 
 func (xn XNumber) Gte(gteValue int, errorMessage ...string) XNumber {
-	return xn.addValidation(
-		"Gte",
-		errors.New(append(errorMessage, fmt.Sprintf("must be greater or equal to: %v", gteValue))[0]),
-		func(value int) bool {
-			return value >= gteValue
-		})
+	return xn.compare("Gte", "gte", gteValue, big.NewInt(int64(gteValue)), "must be greater or equal to: %v", errorMessage, func(cmp int) bool { return cmp >= 0 })
+}
+
+func (xn XNumber) GteBig(gteValue *big.Int, errorMessage ...string) XNumber {
+	gteValue = new(big.Int).Set(gteValue)
+	return xn.compare("Gte", "gte", gteValue, gteValue, "must be greater or equal to: %v", errorMessage, func(cmp int) bool { return cmp >= 0 })
 }
 
 func (xn XNumber) Lt(ltValue int, errorMessage ...string) XNumber {
-	return xn.addValidation(
-		"Lt",
-		errors.New(append(errorMessage, fmt.Sprintf("must be lesser than: %v", ltValue))[0]),
-		func(value int) bool {
-			return value < ltValue
-		})
+	return xn.compare("Lt", "lt", ltValue, big.NewInt(int64(ltValue)), "must be lesser than: %v", errorMessage, func(cmp int) bool { return cmp < 0 })
+}
+
+func (xn XNumber) LtBig(ltValue *big.Int, errorMessage ...string) XNumber {
+	ltValue = new(big.Int).Set(ltValue)
+	return xn.compare("Lt", "lt", ltValue, ltValue, "must be lesser than: %v", errorMessage, func(cmp int) bool { return cmp < 0 })
 }
 
 func (xn XNumber) Lte(lteValue int, errorMessage ...string) XNumber {
+	return xn.compare("Lte", "lte", lteValue, big.NewInt(int64(lteValue)), "must be lesser or equal to: %v", errorMessage, func(cmp int) bool { return cmp <= 0 })
+}
+
+func (xn XNumber) LteBig(lteValue *big.Int, errorMessage ...string) XNumber {
+	lteValue = new(big.Int).Set(lteValue)
+	return xn.compare("Lte", "lte", lteValue, lteValue, "must be lesser or equal to: %v", errorMessage, func(cmp int) bool { return cmp <= 0 })
+}
+
+func (xn XNumber) MultipleOf(mtValue int, errorMessage ...string) XNumber {
+	return xn.multipleOf(mtValue, big.NewInt(int64(mtValue)), errorMessage)
+}
+
+func (xn XNumber) MultipleOfBig(mtValue *big.Int, errorMessage ...string) XNumber {
+	mtValue = new(big.Int).Set(mtValue)
+	return xn.multipleOf(mtValue, mtValue, errorMessage)
+}
+
+func (xn XNumber) OneOf(possibleValues []int, errorMessage ...string) XNumber {
+	values := make([]*big.Int, len(possibleValues))
+
+	for i, v := range possibleValues {
+		values[i] = big.NewInt(int64(v))
+	}
+
+	return xn.oneOf(possibleValues, values, errorMessage)
+}
+
+func (xn XNumber) OneOfBig(possibleValues []*big.Int, errorMessage ...string) XNumber {
+	values := make([]*big.Int, len(possibleValues))
+
+	for i, v := range possibleValues {
+		values[i] = new(big.Int).Set(v)
+	}
+
+	return xn.oneOf(values, values, errorMessage)
+}
+
+func (xn XNumber) compare(ruleName string, paramName string, param interface{}, bound *big.Int, format string, errorMessage []string, test func(cmp int) bool) XNumber {
 	return xn.addValidation(
-		"Lte",
-		errors.New(append(errorMessage, fmt.Sprintf("must be lesser or equal to: %v", lteValue))[0]),
-		func(value int) bool {
-			return value <= lteValue
+		ruleName,
+		map[string]interface{}{paramName: param},
+		append(errorMessage, fmt.Sprintf(format, param))[0],
+		func(value *big.Int) bool {
+			return test(value.Cmp(bound))
 		})
 }
 
-func (xn XNumber) MultipleOf(mtValue int, errorMessage ...string) XNumber {
+func (xn XNumber) multipleOf(param interface{}, mtValue *big.Int, errorMessage []string) XNumber {
 	return xn.addValidation(
 		"MultipleOf",
-		errors.New(append(errorMessage, fmt.Sprintf("must be a multiple of: %v", mtValue))[0]),
-		func(value int) bool {
-			return value%mtValue == 0
+		map[string]interface{}{"multipleOf": param},
+		append(errorMessage, fmt.Sprintf("must be a multiple of: %v", param))[0],
+		func(value *big.Int) bool {
+			if mtValue.Sign() == 0 {
+				return false
+			}
+			return new(big.Int).Rem(value, mtValue).Sign() == 0
 		})
 }
 
-func (xn XNumber) OneOf(possibleValues []int, errorMessage ...string) XNumber {
+func (xn XNumber) oneOf(param interface{}, possibleValues []*big.Int, errorMessage []string) XNumber {
 	return xn.addValidation(
 		"OneOf",
-		errors.New(append(errorMessage, fmt.Sprintf("must be one of: %v", possibleValues))[0]),
-		func(value int) bool {
+		map[string]interface{}{"values": param},
+		append(errorMessage, fmt.Sprintf("must be one of: %v", param))[0],
+		func(value *big.Int) bool {
 			for _, v := range possibleValues {
-				if value == v {
+				if value.Cmp(v) == 0 {
 					return true
 				}
 			}
 			return false
 		})
 }
+
+func (xn XNumber) Custom(ruleName string, check func(*big.Int) error) XNumber {
+	return xn.withValidation(isolate(registry.MustInline(ruleName, helpers.XValidation[*big.Int]{E: helpers.NewError(ruleName, nil, "is invalid"), C: check})))
+}
+
+func (xn XNumber) Refine(ruleName string, check func(*big.Int) bool, errorMessage ...string) XNumber {
+	return xn.withValidation(isolate(registry.MustInline(ruleName, helpers.XValidation[*big.Int]{E: helpers.NewError(ruleName, nil, append(errorMessage, "is invalid")[0]), F: check})))
+}
+
+func (xn XNumber) CustomInt64(ruleName string, check func(int64) error) XNumber {
+	var bigCheck func(*big.Int) error
+
+	if check != nil {
+		bigCheck = func(value *big.Int) error {
+			if !value.IsInt64() {
+				return errInt64Range
+			}
+
+			return check(value.Int64())
+		}
+	}
+
+	return xn.Custom(ruleName, bigCheck)
+}
+
+func (xn XNumber) RefineInt64(ruleName string, check func(int64) bool, errorMessage ...string) XNumber {
+	var bigCheck func(*big.Int) bool
+
+	if check != nil {
+		bigCheck = func(value *big.Int) bool {
+			return value.IsInt64() && check(value.Int64())
+		}
+	}
+
+	return xn.Refine(ruleName, bigCheck, errorMessage...)
+}
+
+var errInt64Range = errors.New("must fit in a 64-bit signed integer")
+
+// Every rule receives the same parsed value, so user callbacks get their own
+// copy and cannot change what later rules see.
+func isolate(validation helpers.XValidation[*big.Int]) helpers.XValidation[*big.Int] {
+	if check := validation.F; check != nil {
+		validation.F = func(value *big.Int) bool { return check(new(big.Int).Set(value)) }
+	}
+
+	if check := validation.C; check != nil {
+		validation.C = func(value *big.Int) error { return check(new(big.Int).Set(value)) }
+	}
+
+	return validation
+}
+
+func toInteger(val interface{}) (*big.Int, error) {
+	if val == nil {
+		return nil, helpers.TypeError("integer", val)
+	}
+
+	if number, ok := val.(json.Number); ok {
+		return numberToInteger(number)
+	}
+
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		floatValue := rv.Float()
+
+		if math.IsNaN(floatValue) || math.IsInf(floatValue, 0) || floatValue != math.Trunc(floatValue) {
+			return nil, helpers.NewError("Integer", nil, "must be an integer").WithValue(val)
+		}
+
+		value, _ := big.NewFloat(floatValue).Int(nil)
+		return value, nil
+	}
+
+	return nil, helpers.TypeError("integer", val)
+}
+
+// Exponent-form numbers are checked against the 64-bit range before they are
+// expanded, so a short literal such as 1e600000000 cannot allocate its digits.
+func numberToInteger(number json.Number) (*big.Int, error) {
+	if value, ok := new(big.Int).SetString(string(number), 10); ok {
+		return value, nil
+	}
+
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(string(number)), "e")
+	digits := strings.TrimLeft(mantissa, "+-")
+
+	if exponent != "" {
+		exp, err := strconv.Atoi(exponent)
+
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return nil, helpers.TypeError("integer", number)
+		}
+
+		if strings.Trim(digits, "0.") == "" {
+			return new(big.Int), nil
+		}
+
+		if err != nil || exp > len(digits)+20 {
+			return nil, rangeError(number)
+		}
+
+		if exp < -len(digits) {
+			return nil, helpers.NewError("Integer", nil, "must be an integer").WithValue(number)
+		}
+	}
+
+	rat, ok := new(big.Rat).SetString(string(number))
+
+	if !ok {
+		return nil, helpers.TypeError("integer", number)
+	}
+
+	if !rat.IsInt() {
+		return nil, helpers.NewError("Integer", nil, "must be an integer").WithValue(number)
+	}
+
+	value := rat.Num()
+
+	if exponent != "" && !value.IsInt64() && !value.IsUint64() {
+		return nil, rangeError(number)
+	}
+
+	return value, nil
+}
+
+func rangeError(number json.Number) *helpers.ValidationError {
+	return helpers.NewError("Integer", nil, "must be within the 64-bit integer range when written with an exponent").WithValue(number)
+}
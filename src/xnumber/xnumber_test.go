@@ -1,9 +1,13 @@
 package xnumber_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"testing"
 
+	"github.com/radchukd/go-xschema/src/helpers"
 	"github.com/radchukd/go-xschema/src/xnumber"
 )
 
@@ -22,6 +26,12 @@ func TestValidate(t *testing.T) {
 		t.Errorf("Validate(%v) -> false; want true", floatValue)
 	}
 
+	fractionValue := 17.9
+
+	if isValid, _ := xn.Validate(fractionValue); isValid {
+		t.Errorf("Validate(%v) -> true; want false", fractionValue)
+	}
+
 	value := ""
 
 	if isValid, _ := xn.Validate(value); isValid {
@@ -170,3 +180,298 @@ func TestOneOf(t *testing.T) {
 		t.Errorf("OneOf(%v,%v) -> true; want false", possibleValues, value)
 	}
 }
+
+func TestIntegerKinds(t *testing.T) {
+	xn := xnumber.Create().Gte(0)
+
+	values := []interface{}{int8(1), int16(1), int32(1), int64(1), uint(1), uint8(1), uint16(1), uint32(1), uint64(1), json.Number("1")}
+
+	for _, value := range values {
+		if isValid, _ := xn.Validate(value); !isValid {
+			t.Errorf("Validate(%T(%v)) -> false; want true", value, value)
+		}
+	}
+
+	var bigValue uint64 = math.MaxUint64
+	xn = xnumber.Create().Gt(math.MaxInt64)
+
+	if isValid, _ := xn.Validate(bigValue); !isValid {
+		t.Errorf("Gt(%v,%v) -> false; want true", math.MaxInt64, bigValue)
+	}
+
+	xn = xnumber.Create().Lte(math.MaxInt64)
+
+	if isValid, _ := xn.Validate(bigValue); isValid {
+		t.Errorf("Lte(%v,%v) -> true; want false", math.MaxInt64, bigValue)
+	}
+
+	exactValue := json.Number("9007199254740993")
+	xn = xnumber.Create().OneOf([]int{9007199254740993})
+
+	if isValid, _ := xn.Validate(exactValue); !isValid {
+		t.Errorf("OneOf(%v) -> false; want true", exactValue)
+	}
+
+	fractionValue := json.Number("1.5")
+
+	if isValid, _ := xn.Validate(fractionValue); isValid {
+		t.Errorf("Validate(%v) -> true; want false", fractionValue)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	xn := xnumber.Create().Gt(10).MultipleOf(4).Lt(20)
+
+	if out := xn.String(); out != "XNumber(Gt,MultipleOf,Lt,)" {
+		t.Errorf("String() -> %s; want XNumber(Gt,MultipleOf,Lt,)", out)
+	}
+
+	value := 7
+
+	for i := 0; i < 10; i++ {
+		_, errs := xn.Validate(value)
+
+		if len(errs) != 2 || errs[0].(*helpers.ValidationError).Rule != "Gt" || errs[1].(*helpers.ValidationError).Rule != "MultipleOf" {
+			t.Fatalf("Validate(%v) -> %v; want [Gt MultipleOf]", value, errs)
+		}
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	value := 7
+	base := xnumber.Create().Gt(0)
+	a := base.Lt(5)
+	b := base.Lt(10)
+
+	if isValid, _ := a.Validate(value); isValid {
+		t.Errorf("Lt(5,%v) -> true; want false", value)
+	}
+
+	if isValid, _ := b.Validate(value); !isValid {
+		t.Errorf("Lt(10,%v) -> false; want true", value)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	xn := xnumber.Create()
+
+	if isValid, _ := xn.Validate(nil); isValid {
+		t.Errorf("Validate(nil) -> true; want false")
+	}
+
+	xn = xn.Nullable()
+
+	if isValid, _ := xn.Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+
+	if isValid, _ := xn.Validate(1); !isValid {
+		t.Errorf("Nullable(%v) -> false; want true", 1)
+	}
+}
+
+func TestFromTags(t *testing.T) {
+	xn, err := xnumber.FromTags([]string{"Required", "Gte=18", "OneOf=[18,21]"})
+
+	if err != nil || xn.String() != "XNumber(Required,Gte,OneOf,)" {
+		t.Errorf("FromTags() -> %s, %v; want XNumber(Required,Gte,OneOf,)", xn.String(), err)
+	}
+
+	for _, tag := range []string{"Gte=abc", "OneOf=[a]", "Gtee=1"} {
+		if _, err := xnumber.FromTags([]string{tag}); err == nil {
+			t.Errorf("FromTags(%s) -> nil; want error", tag)
+		}
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	err := xnumber.RegisterRule("Even", func(args []string) (helpers.XValidation[*big.Int], error) {
+		return helpers.XValidation[*big.Int]{
+			E: helpers.NewError("Even", nil, "must be even"),
+			F: func(val *big.Int) bool { return val.Bit(0) == 0 },
+		}, helpers.NoTagArgs(args)
+	})
+
+	if err != nil {
+		t.Fatalf("RegisterRule(Even) -> %v; want nil", err)
+	}
+
+	xn := xnumber.MustFromTags([]string{"Gte=0", "Even"})
+
+	if isValid, _ := xn.Validate(4); !isValid {
+		t.Errorf("Even(%v) -> false; want true", 4)
+	}
+
+	if isValid, _ := xn.Validate(3); isValid {
+		t.Errorf("Even(%v) -> true; want false", 3)
+	}
+
+	for _, name := range []string{"Gt", "When", "Items", "MaxDepth"} {
+		if err := xnumber.RegisterRule(name, func(args []string) (helpers.XValidation[*big.Int], error) {
+			return helpers.XValidation[*big.Int]{}, nil
+		}); err == nil {
+			t.Errorf("RegisterRule(%s) -> nil; want collision error", name)
+		}
+	}
+}
+
+func TestCustom(t *testing.T) {
+	xn := xnumber.Create().Gt(0).Custom("Port", func(value *big.Int) error {
+		if value.Cmp(big.NewInt(65535)) > 0 {
+			return fmt.Errorf("port %s is out of range", value)
+		}
+		return nil
+	})
+
+	if out := xn.String(); out != "XNumber(Gt,Port,)" {
+		t.Errorf("String() -> %s; want XNumber(Gt,Port,)", out)
+	}
+
+	if isValid, _ := xn.Validate(8080); !isValid {
+		t.Errorf("Custom(%v) -> false; want true", 8080)
+	}
+
+	if _, errs := xn.Validate(70000); len(errs) != 1 || errs[0].Error() != "port 70000 is out of range" {
+		t.Errorf("Custom(%v) -> %v; want [port 70000 is out of range]", 70000, errs)
+	}
+}
+
+func TestRefine(t *testing.T) {
+	xn := xnumber.Create().Refine("Even", func(value *big.Int) bool { return value.Bit(0) == 0 }, "must be even")
+
+	if isValid, _ := xn.Validate(4); !isValid {
+		t.Errorf("Refine(%v) -> false; want true", 4)
+	}
+
+	if _, errs := xn.Validate(5); len(errs) != 1 || errs[0].(*helpers.ValidationError).Rule != "Even" {
+		t.Errorf("Refine(%v) -> %v; want [Even]", 5, errs)
+	}
+}
+
+func TestRefineCopy(t *testing.T) {
+	xn := xnumber.Create().
+		Refine("Mutate", func(value *big.Int) bool { value.Add(value, big.NewInt(100)); return true }).
+		Lte(10)
+
+	if isValid, errs := xn.Validate(5); !isValid {
+		t.Errorf("Refine(Mutate).Lte(10, 5) -> %v; want true", errs)
+	}
+}
+
+func TestCustomInt64(t *testing.T) {
+	xn := xnumber.Create().CustomInt64("Port", func(value int64) error {
+		if value > 65535 {
+			return fmt.Errorf("port %d is out of range", value)
+		}
+		return nil
+	}).RefineInt64("Even", func(value int64) bool { return value%2 == 0 }, "must be even")
+
+	if out := xn.String(); out != "XNumber(Port,Even,)" {
+		t.Errorf("String() -> %s; want XNumber(Port,Even,)", out)
+	}
+
+	if isValid, _ := xn.Validate(8080); !isValid {
+		t.Errorf("CustomInt64(%v) -> false; want true", 8080)
+	}
+
+	if _, errs := xn.Validate(70001); len(errs) != 2 || errs[0].Error() != "port 70001 is out of range" {
+		t.Errorf("CustomInt64(%v) -> %v; want Port and Even errors", 70001, errs)
+	}
+
+	if _, errs := xn.Validate(uint64(math.MaxUint64)); len(errs) != 2 {
+		t.Errorf("CustomInt64(MaxUint64) -> %v; want Port and Even errors", errs)
+	}
+}
+
+func TestBigBounds(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	xn := xnumber.Create().GteBig(big.NewInt(0)).LteBig(maxUint64)
+
+	if isValid, _ := xn.Validate(uint64(math.MaxUint64)); !isValid {
+		t.Errorf("LteBig(%v, MaxUint64) -> false; want true", maxUint64)
+	}
+
+	if isValid, _ := xn.Validate(json.Number("18446744073709551616")); isValid {
+		t.Errorf("LteBig(%v, MaxUint64+1) -> true; want false", maxUint64)
+	}
+
+	if isValid, _ := xnumber.Create().GtBig(maxUint64).Validate(uint64(math.MaxUint64)); isValid {
+		t.Errorf("GtBig(%v, MaxUint64) -> true; want false", maxUint64)
+	}
+
+	if isValid, _ := xnumber.Create().LtBig(big.NewInt(0)).Validate(-1); !isValid {
+		t.Errorf("LtBig(0, -1) -> false; want true")
+	}
+
+	if isValid, _ := xnumber.Create().MultipleOfBig(maxUint64).Validate(uint64(math.MaxUint64)); !isValid {
+		t.Errorf("MultipleOfBig(%v, MaxUint64) -> false; want true", maxUint64)
+	}
+
+	if isValid, _ := xnumber.Create().OneOfBig([]*big.Int{maxUint64}).Validate(uint64(math.MaxUint64)); !isValid {
+		t.Errorf("OneOfBig(%v, MaxUint64) -> false; want true", maxUint64)
+	}
+
+	bound := big.NewInt(10)
+	xn = xnumber.Create().LteBig(bound)
+	bound.SetInt64(0)
+
+	if isValid, _ := xn.Validate(5); !isValid {
+		t.Errorf("LteBig(10, 5) -> false; want the bound to be copied")
+	}
+}
+
+func TestFromTagsBig(t *testing.T) {
+	xn, err := xnumber.FromTags([]string{"Gt=-9223372036854775809", "Lte=18446744073709551615", "OneOf(1,18446744073709551615)"})
+
+	if err != nil {
+		t.Fatalf("FromTags() -> %v; want nil", err)
+	}
+
+	if isValid, _ := xn.Validate(uint64(math.MaxUint64)); !isValid {
+		t.Errorf("FromTags(MaxUint64) -> false; want true")
+	}
+
+	if _, err := xnumber.FromTags([]string{"Lte=1.5"}); err == nil {
+		t.Errorf("FromTags(Lte=1.5) -> nil; want tag error")
+	}
+}
+
+func TestCustomInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Refine(Required) did not panic")
+		}
+	}()
+
+	xnumber.Create().Refine("Required", func(*big.Int) bool { return true })
+}
+
+func TestCustomInt64Nil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CustomInt64(nil) did not panic")
+		}
+	}()
+
+	xnumber.Create().CustomInt64("Port", nil)
+}
+
+func TestExponentNumbers(t *testing.T) {
+	xn := xnumber.Create().Lt(10)
+
+	valid := []json.Number{"1e0", "5E0", "0.5e1", "-1.2e1", "0e999999999999", "9.2233720368547758e18"}
+
+	for _, value := range valid {
+		if isValid, errs := xnumber.Create().Validate(value); !isValid {
+			t.Errorf("Validate(%v) -> %v; want true", value, errs)
+		}
+	}
+
+	invalid := []json.Number{"1e600000000", "-1e600000000", "1e99999999999999999999", "1e-600000000", "1.5e0", "1e20", "1e", "1ee2"}
+
+	for _, value := range invalid {
+		if isValid, _ := xn.Validate(value); isValid {
+			t.Errorf("Lt(10,%v) -> true; want false", value)
+		}
+	}
+}
@@ -1,21 +1,108 @@
 package xschema
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xarray"
+	"github.com/radchukd/go-xschema/src/xbool"
+	"github.com/radchukd/go-xschema/src/xfloat"
 	"github.com/radchukd/go-xschema/src/xnumber"
 	"github.com/radchukd/go-xschema/src/xstring"
+	"github.com/radchukd/go-xschema/src/xtag"
 )
 
 var tagName = "x"
 
+var compiledSchemas sync.Map
+
+const DefaultMaxDepth = 64
+
+type UnknownKeyPolicy int
+
+// Passthrough and Strip both accept unknown keys when validating; Strip
+// additionally removes them from the map returned by Parse. Strict reports
+// every unknown key as an "UnknownKey" error.
+const (
+	Passthrough UnknownKeyPolicy = iota
+	Strip
+	Strict
+)
+
 type XSchema struct {
-	values map[string]helpers.XObject
+	optional      bool
+	nullable      bool
+	required      bool
+	jsonTags      bool
+	ignoreMissing bool
+	unknownKeys   UnknownKeyPolicy
+	keys          []string
+	values        map[string]helpers.XObject
+	presence      map[string]bool
+	refinements   []refinement
+}
+
+type XWhen struct {
+	condition func(values map[string]interface{}) bool
+	then      helpers.XObject
+	otherwise helpers.XObject
+}
+
+type XUnion struct {
+	optional      bool
+	nullable      bool
+	required      bool
+	jsonTags      bool
+	discriminator string
+	cases         []string
+	branches      map[string]XSchema
+}
+
+type XComposite struct {
+	optional bool
+	nullable bool
+	op       string
+	children []helpers.XObject
+}
+
+type XLazy struct {
+	optional bool
+	nullable bool
+	required bool
+	maxDepth int
+	resolved *lazyObject
+}
+
+type lazyObject struct {
+	once    sync.Once
+	factory func() helpers.XObject
+	xo      helpers.XObject
+}
+
+type visitKey struct {
+	validator interface{}
+	t         reflect.Type
+	ptr       uintptr
+	n         int
+}
+
+type schemaKey struct {
+	values        uintptr
+	presence      uintptr
+	refinements   uintptr
+	jsonTags      bool
+	ignoreMissing bool
+	unknownKeys   UnknownKeyPolicy
+}
+
+type refinement struct {
+	key     string
+	aliases map[string]string
+	check   func(values map[string]interface{}) error
 }
 
 func Create() XSchema {
@@ -25,130 +112,1576 @@ func Create() XSchema {
 }
 
 func Clone(schema XSchema) XSchema {
-	newSchema := XSchema{}
-	newSchema.values = make(map[string]helpers.XObject)
+	return schema
+}
 
-	for k, v := range schema.values {
-		newSchema.values[k] = v
+func Merge(s1 XSchema, s2 XSchema) XSchema {
+	newSchema := Clone(s1)
+
+	for _, k := range s2.keys {
+		newSchema = newSchema.add(k, s2.values[k])
+	}
+
+	for k, required := range s2.presence {
+		newSchema = newSchema.withPresence([]string{k}, required)
+	}
+
+	for _, r := range s2.refinements {
+		newSchema = newSchema.addRefinement(r)
 	}
 
 	return newSchema
 }
 
-func Merge(s1 XSchema, s2 XSchema) XSchema {
-	newSchema := XSchema{}
-	newSchema.values = make(map[string]helpers.XObject)
+func (schema XSchema) add(key string, xo helpers.XObject) XSchema {
+	values := make(map[string]helpers.XObject, len(schema.values)+1)
+
+	for k, v := range schema.values {
+		values[k] = v
+	}
+
+	if _, ok := values[key]; !ok {
+		keys := make([]string, len(schema.keys), len(schema.keys)+1)
+		copy(keys, schema.keys)
+		schema.keys = append(keys, key)
+	}
+
+	values[key] = xo
+	schema.values = values
+
+	if _, ok := schema.presence[key]; ok {
+		schema = schema.withoutPresence(key)
+	}
+
+	return schema
+}
+
+func (schema XSchema) withPresence(keys []string, required bool) XSchema {
+	presence := make(map[string]bool, len(schema.presence)+len(keys))
+
+	for k, v := range schema.presence {
+		presence[k] = v
+	}
+
+	for _, k := range keys {
+		if _, ok := schema.values[k]; ok {
+			presence[k] = required
+		}
+	}
 
-	for k, v := range s1.values {
-		newSchema.values[k] = v
+	schema.presence = presence
+	return schema
+}
+
+func (schema XSchema) withoutPresence(keys ...string) XSchema {
+	presence := make(map[string]bool, len(schema.presence))
+
+	for k, v := range schema.presence {
+		presence[k] = v
+	}
+
+	for _, k := range keys {
+		delete(presence, k)
+	}
+
+	schema.presence = presence
+	return schema
+}
+
+func (schema XSchema) filter(keep func(key string) bool) XSchema {
+	newSchema := schema
+	newSchema.keys = make([]string, 0, len(schema.keys))
+	newSchema.values = make(map[string]helpers.XObject, len(schema.values))
+	newSchema.presence = make(map[string]bool, len(schema.presence))
+	newSchema.refinements = make([]refinement, 0, len(schema.refinements))
+
+	for _, r := range schema.refinements {
+		if r.key == "" || keep(r.key) {
+			newSchema.refinements = append(newSchema.refinements, r)
+		}
 	}
 
-	for k, v := range s2.values {
-		newSchema.values[k] = v
+	for _, key := range schema.keys {
+		if !keep(key) {
+			continue
+		}
+
+		newSchema.keys = append(newSchema.keys, key)
+		newSchema.values[key] = schema.values[key]
+
+		if required, ok := schema.presence[key]; ok {
+			newSchema.presence[key] = required
+		}
 	}
 
 	return newSchema
 }
 
-func (schema XSchema) AddString(key string, xs xstring.XString) XSchema {
-	schema.values[key] = xs
+func (schema XSchema) addRefinement(r refinement) XSchema {
+	refinements := make([]refinement, len(schema.refinements), len(schema.refinements)+1)
+	copy(refinements, schema.refinements)
+
+	schema.refinements = append(refinements, r)
 	return schema
 }
 
-func (schema XSchema) AddNumber(key string, xn xnumber.XNumber) XSchema {
-	schema.values[key] = xn
+func (schema XSchema) UseJSONTags() XSchema {
+	schema.jsonTags = true
 	return schema
 }
 
-func (schema XSchema) ValidateKey(schemaKey string, value interface{}) (bool, []error) {
-	for key, val := range schema.values {
-		if key == schemaKey {
-			if isValid, errors := val.Validate(value); !isValid {
-				return false, errors
-			}
+func (schema XSchema) AddString(key string, xs xstring.XString) XSchema {
+	return schema.add(key, xs)
+}
+
+func (schema XSchema) AddNumber(key string, xn xnumber.XNumber) XSchema {
+	return schema.add(key, xn)
+}
+
+func (schema XSchema) AddFloat(key string, xf xfloat.XFloat) XSchema {
+	return schema.add(key, xf)
+}
+
+func (schema XSchema) AddBool(key string, xb xbool.XBool) XSchema {
+	return schema.add(key, xb)
+}
 
+func (schema XSchema) AddArray(key string, xa xarray.XArray) XSchema {
+	return schema.add(key, xa)
+}
+
+func (schema XSchema) AddObject(key string, xo XSchema) XSchema {
+	return schema.add(key, xo)
+}
+
+func (schema XSchema) AddWhen(key string, xw XWhen) XSchema {
+	return schema.add(key, xw)
+}
+
+func (schema XSchema) AddUnion(key string, xu XUnion) XSchema {
+	return schema.add(key, xu)
+}
+
+func (schema XSchema) AddComposite(key string, xc XComposite) XSchema {
+	return schema.add(key, xc)
+}
+
+func (schema XSchema) AddLazy(key string, xl XLazy) XSchema {
+	return schema.add(key, xl)
+}
+
+func (schema XSchema) Validate(val interface{}) (bool, []error) {
+	return schema.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (schema XSchema) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	if helpers.Indirect(val) == nil {
+		if schema.nullable {
 			return true, nil
 		}
+
+		return false, []error{helpers.NullError()}
 	}
 
-	return true, nil
-}
+	defer ctx.UseJSONTags(schema.jsonTags)()
 
-func (schema XSchema) SValidateKey(schemaKey string, value interface{}) (bool, []error) {
-	for key, val := range schema.values {
-		if key == schemaKey {
-			if isValid, errors := val.Validate(value); !isValid {
-				return false, errors
-			}
+	values, ok := toMap(val, ctx.JSONTags)
+
+	if !ok {
+		return false, []error{helpers.TypeError("object", val)}
+	}
 
+	if key, ok := schema.identity(val); ok {
+		if !ctx.Enter(key) {
 			return true, nil
 		}
+
+		defer ctx.Leave(key)
 	}
 
-	errs := make([]error, 0)
-	return false, append(errs, errors.New("invalid key"))
+	validationErrors := schema.validateMap(values, ctx)
+
+	return len(validationErrors) == 0, validationErrors
 }
 
-func (schema XSchema) ValidateMap(values map[string]interface{}) (bool, map[string][]error) {
-	validationErrors := make(map[string][]error)
+func (schema XSchema) String() string {
+	out := "XSchema("
 
-	for key, value := range values {
-		if isValid, errors := schema.ValidateKey(key, value); !isValid {
-			validationErrors[fmt.Sprintf("%s(%v)", key, value)] = errors
+	if schema.optional {
+		out += "Optional,"
+	}
+
+	if schema.nullable {
+		out += "Nullable,"
+	}
+
+	if schema.required {
+		out += "Required,"
+	}
+
+	for _, key := range schema.keys {
+		out += key + ":" + schema.values[key].String() + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func (schema XSchema) Optional() XSchema {
+	schema.optional = true
+	return schema
+}
+
+func (schema XSchema) Nullable() XSchema {
+	schema.nullable = true
+	return schema
+}
+
+func (schema XSchema) IsOptional() bool {
+	return schema.optional
+}
+
+func (schema XSchema) IsNullable() bool {
+	return schema.nullable
+}
+
+func (schema XSchema) Required() XSchema {
+	schema.required = true
+	return schema
+}
+
+func (schema XSchema) IsRequired() bool {
+	if schema.required {
+		return true
+	}
+
+	if schema.ignoreMissing {
+		return false
+	}
+
+	for _, key := range schema.keys {
+		if schema.requires(key, schema.values[key]) {
+			return true
 		}
 	}
 
-	return len(validationErrors) == 0, validationErrors
+	return false
 }
 
-func (schema XSchema) SValidateMap(values map[string]interface{}) (bool, map[string][]error) {
-	validationErrors := make(map[string][]error)
+func (schema XSchema) IgnoreMissing() XSchema {
+	schema.ignoreMissing = true
+	return schema
+}
 
-	for key, value := range values {
-		if isValid, errors := schema.SValidateKey(key, value); !isValid {
-			validationErrors[fmt.Sprintf("%s(%v)", key, value)] = errors
+func (schema XSchema) UnknownKeys(policy UnknownKeyPolicy) XSchema {
+	schema.unknownKeys = policy
+	return schema
+}
+
+func (schema XSchema) Pick(keys ...string) XSchema {
+	picked := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+		picked[key] = true
+	}
+
+	return schema.filter(func(key string) bool { return picked[key] })
+}
+
+func (schema XSchema) Omit(keys ...string) XSchema {
+	omitted := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+		omitted[key] = true
+	}
+
+	return schema.filter(func(key string) bool { return !omitted[key] })
+}
+
+func (schema XSchema) Partial(keys ...string) XSchema {
+	if len(keys) == 0 {
+		keys = schema.keys
+	}
+
+	return schema.withPresence(keys, false)
+}
+
+func (schema XSchema) RequiredAll(keys ...string) XSchema {
+	if len(keys) == 0 {
+		keys = schema.keys
+	}
+
+	return schema.withPresence(keys, true)
+}
+
+func (schema XSchema) Extend(other XSchema) XSchema {
+	return Merge(schema, other)
+}
+
+func (schema XSchema) Rename(from string, to string) XSchema {
+	xo, ok := schema.values[from]
+
+	if !ok || from == to {
+		return schema
+	}
+
+	required, hasPresence := schema.presence[from]
+	newSchema := schema.Omit(to)
+	keys := make([]string, len(newSchema.keys))
+
+	for i, key := range newSchema.keys {
+		if key == from {
+			key = to
 		}
+
+		keys[i] = key
 	}
 
-	return len(validationErrors) == 0, validationErrors
+	newSchema.keys = keys
+	delete(newSchema.values, from)
+	newSchema.values[to] = xo
+	delete(newSchema.presence, from)
+
+	if hasPresence {
+		newSchema.presence[to] = required
+	}
+
+	for i, r := range newSchema.refinements {
+		newSchema.refinements[i] = r.rename(from, to)
+	}
+
+	return newSchema
+}
+
+func (schema XSchema) Refine(ruleName string, check func(values map[string]interface{}) bool, errorMessage ...string) XSchema {
+	return schema.RefineKey("", ruleName, check, errorMessage...)
+}
+
+func (schema XSchema) RefineKey(key string, ruleName string, check func(values map[string]interface{}) bool, errorMessage ...string) XSchema {
+	message := append(errorMessage, "is invalid")[0]
+
+	return schema.addRefinement(refinement{key: key, check: func(values map[string]interface{}) error {
+		if check(values) {
+			return nil
+		}
+
+		return helpers.NewError(ruleName, nil, message)
+	}})
+}
+
+func (schema XSchema) Custom(ruleName string, check func(values map[string]interface{}) error) XSchema {
+	return schema.addRefinement(refinement{check: func(values map[string]interface{}) error {
+		err := check(values)
+
+		if err == nil {
+			return nil
+		}
+
+		ve, ok := err.(*helpers.ValidationError)
+
+		if !ok {
+			return helpers.NewError(ruleName, nil, err.Error())
+		}
+
+		if ve.Rule == "" {
+			ve = ve.WithValue(ve.Value)
+			ve.Rule = ruleName
+		}
+
+		return ve
+	}})
+}
+
+func (schema XSchema) ValidateKey(schemaKey string, value interface{}) (bool, []error) {
+	if val, ok := schema.values[schemaKey]; ok {
+		if isValid, errors := val.Validate(value); !isValid {
+			return false, errors
+		}
+
+		return true, nil
+	}
+
+	if schema.unknownKeys == Strict {
+		return false, []error{helpers.NewError("UnknownKey", nil, "invalid key").WithValue(value)}
+	}
+
+	return true, nil
+}
+
+// Deprecated: use UnknownKeys(Strict).ValidateKey instead.
+func (schema XSchema) SValidateKey(schemaKey string, value interface{}) (bool, []error) {
+	return schema.UnknownKeys(Strict).ValidateKey(schemaKey, value)
+}
+
+func (schema XSchema) ValidateMap(values map[string]interface{}) (bool, map[string][]error) {
+	return schema.validateRoot(values, values)
+}
+
+// Deprecated: use UnknownKeys(Strict).ValidateMap instead.
+func (schema XSchema) SValidateMap(values map[string]interface{}) (bool, map[string][]error) {
+	return schema.UnknownKeys(Strict).ValidateMap(values)
 }
 
 func (schema XSchema) ValidateStruct(obj interface{}) (bool, map[string][]error) {
-	var mappedObj map[string]interface{}
-	inrec, _ := json.Marshal(obj)
-	json.Unmarshal(inrec, &mappedObj)
+	mappedObj, ok := toMap(obj, schema.jsonTags)
 
-	return schema.ValidateMap(mappedObj)
+	if !ok {
+		return false, map[string][]error{"": {helpers.TypeError("object", obj)}}
+	}
+
+	return schema.validateRoot(obj, mappedObj)
 }
 
+// Deprecated: use UnknownKeys(Strict).ValidateStruct instead.
 func (schema XSchema) SValidateStruct(obj interface{}) (bool, map[string][]error) {
-	var mappedObj map[string]interface{}
-	inrec, _ := json.Marshal(obj)
-	json.Unmarshal(inrec, &mappedObj)
-	return schema.SValidateMap(mappedObj)
+	return schema.UnknownKeys(Strict).ValidateStruct(obj)
 }
 
-func ValidateTaggedStruct(obj interface{}) (bool, map[string][]error) {
-	schema := Create()
-	t := reflect.TypeOf(obj)
+func (schema XSchema) Parse(values map[string]interface{}) (map[string]interface{}, bool, map[string][]error) {
+	isValid, validationErrors := schema.ValidateMap(values)
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		tag := field.Tag.Get(tagName)
+	if schema.unknownKeys != Strip {
+		return values, isValid, validationErrors
+	}
 
-		if tag == "" {
-			continue
+	parsed := make(map[string]interface{}, len(schema.keys))
+
+	for _, key := range schema.keys {
+		if value, ok := values[key]; ok {
+			if nested, ok := resolve(schema.values[key], values).(XSchema); ok {
+				if nestedValues, ok := value.(map[string]interface{}); ok {
+					value, _, _ = nested.Parse(nestedValues)
+				}
+			}
+
+			parsed[key] = value
 		}
+	}
+
+	return parsed, isValid, validationErrors
+}
 
-		validationTags := strings.Split(tag, ",")
+func ValidateTaggedStruct(obj interface{}) (bool, map[string][]error) {
+	schema, err := CompileTagged(reflect.TypeOf(obj))
+
+	if err != nil {
+		path := ""
 
-		switch field.Type.Kind() {
-		case reflect.String:
-			schema = schema.AddString(field.Name, xstring.FromTags(validationTags))
-		default:
-			schema = schema.AddNumber(field.Name, xnumber.FromTags(validationTags))
+		if te, ok := err.(*helpers.TagError); ok {
+			path = te.Field
 		}
+
+		return false, map[string][]error{path: {&helpers.ValidationError{Path: path, Rule: "Tag", Message: err.Error()}}}
 	}
 
 	return schema.ValidateStruct(obj)
 }
+
+func SchemaFor[T any]() (XSchema, error) {
+	return CompileTagged(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func MustSchemaFor[T any]() XSchema {
+	return MustCompileTagged(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func CompileTagged(t reflect.Type) (XSchema, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return Create(), &helpers.TagError{Err: fmt.Errorf("unsupported type: %v", t)}
+	}
+
+	if cached, ok := compiledSchemas.Load(t); ok {
+		return cached.(XSchema), nil
+	}
+
+	schema, err := fromType(t, make(map[reflect.Type]bool))
+
+	if err != nil {
+		return schema, err
+	}
+
+	cached, _ := compiledSchemas.LoadOrStore(t, schema)
+
+	return cached.(XSchema), nil
+}
+
+func MustCompileTagged(t reflect.Type) XSchema {
+	schema, err := CompileTagged(t)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+func fromType(t reflect.Type, visiting map[reflect.Type]bool) (XSchema, error) {
+	schema := Create()
+	promoted := Create()
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		fieldType := field.Type
+
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			if visiting[fieldType] {
+				continue
+			}
+
+			embedded, err := fromType(fieldType, visiting)
+
+			if err != nil {
+				return schema, err
+			}
+
+			promoted = Merge(promoted, embedded)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag == "" {
+			structType := fieldType
+
+			if structType.Kind() == reflect.Slice || structType.Kind() == reflect.Array {
+				structType = structType.Elem()
+
+				for structType.Kind() == reflect.Pointer {
+					structType = structType.Elem()
+				}
+			}
+
+			if structType.Kind() != reflect.Struct || !hasExportedFields(structType) {
+				continue
+			}
+
+			nested, err := fromField(structType, nil, visiting)
+
+			if err == nil && field.Type != structType {
+				if xs, ok := nested.(XSchema); ok && len(xs.keys) == 0 {
+					continue
+				}
+
+				nested, err = fromField(field.Type, nil, visiting)
+			}
+
+			if err != nil {
+				return schema, helpers.WithTagField(field.Name, err)
+			}
+
+			if xs, ok := nested.(XSchema); !ok || len(xs.keys) > 0 {
+				schema = schema.add(field.Name, nested)
+			}
+
+			continue
+		}
+
+		rules, err := xtag.Parse(tag)
+
+		if err != nil {
+			return schema, helpers.WithTagField(field.Name, &helpers.TagError{Err: err})
+		}
+
+		xo, err := fromField(field.Type, rules, visiting)
+
+		if err != nil {
+			return schema, helpers.WithTagField(field.Name, err)
+		}
+
+		schema = schema.add(field.Name, xo)
+	}
+
+	for _, key := range promoted.keys {
+		if _, ok := schema.values[key]; !ok {
+			schema = schema.add(key, promoted.values[key])
+		}
+	}
+
+	return schema, nil
+}
+
+func fromField(t reflect.Type, rules []xtag.Rule, visiting map[reflect.Type]bool) (helpers.XObject, error) {
+	for i, rule := range rules {
+		if rule.Name == "When" {
+			base := make([]xtag.Rule, 0, len(rules)-1)
+			base = append(append(base, rules[:i]...), rules[i+1:]...)
+			return fromWhen(t, base, rule, visiting)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if len(rules) == 0 || hasTagRule(rules, "Optional") {
+			rules = append(rules[:len(rules):len(rules)], xtag.Rule{Name: "Nullable", Args: []string{}})
+		}
+
+		return fromField(t.Elem(), rules, visiting)
+	case reflect.String:
+		return xstring.FromRules(rules)
+	case reflect.Bool:
+		return xbool.FromRules(rules)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return xnumber.FromRules(rules)
+	case reflect.Float32, reflect.Float64:
+		return xfloat.FromRules(rules)
+	case reflect.Slice, reflect.Array:
+		arrayRules := make([]xtag.Rule, 0, len(rules))
+		itemRules := make([]xtag.Rule, 0)
+
+		for _, rule := range rules {
+			if rule.Name != "Items" {
+				arrayRules = append(arrayRules, rule)
+				continue
+			}
+
+			for _, arg := range rule.Args {
+				parsed, err := xtag.Parse(arg)
+
+				if err != nil {
+					return nil, &helpers.TagError{Rule: rule.Name, Arg: strings.Join(rule.Args, ","), Err: err}
+				}
+
+				itemRules = append(itemRules, parsed...)
+			}
+		}
+
+		xa, err := xarray.FromRules(arrayRules)
+
+		if err != nil {
+			return xa, err
+		}
+
+		if t.Elem().Kind() == reflect.Interface {
+			if len(itemRules) > 0 {
+				return nil, &helpers.TagError{Rule: "Items", Err: fmt.Errorf("unsupported element type: %s", t.Elem())}
+			}
+
+			return xa, nil
+		}
+
+		element, err := fromField(t.Elem(), itemRules, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return xa.Of(element), nil
+	case reflect.Struct:
+		if !hasExportedFields(t) {
+			break
+		}
+
+		optional, nullable, required := false, false, false
+		maxDepth := DefaultMaxDepth
+
+		for _, rule := range rules {
+			err := helpers.NoTagArgs(rule.Args)
+
+			switch rule.Name {
+			case "Optional":
+				optional = true
+			case "Nullable":
+				nullable = true
+			case "Required":
+				required = true
+			case "MaxDepth":
+				if maxDepth, err = helpers.IntTagArg(rule.Args); err == nil && !visiting[t] {
+					err = fmt.Errorf("only applies to recursive fields")
+				}
+			default:
+				err = helpers.ErrUnknownRule
+			}
+
+			if err != nil {
+				return nil, &helpers.TagError{Rule: rule.Name, Arg: strings.Join(rule.Args, ","), Err: err}
+			}
+		}
+
+		if visiting[t] {
+			xl := Lazy(func() helpers.XObject {
+				schema, _ := CompileTagged(t)
+				return schema
+			}).MaxDepth(maxDepth)
+
+			if optional {
+				xl = xl.Optional()
+			}
+
+			if nullable {
+				xl = xl.Nullable()
+			}
+
+			if required {
+				xl = xl.Required()
+			}
+
+			return xl, nil
+		}
+
+		schema, err := fromType(t, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if optional {
+			schema = schema.Optional()
+		}
+
+		if nullable {
+			schema = schema.Nullable()
+		}
+
+		if required {
+			schema = schema.Required()
+		}
+
+		return schema, nil
+	}
+
+	return nil, &helpers.TagError{Err: fmt.Errorf("unsupported type: %s", t)}
+}
+
+func hasTagRule(rules []xtag.Rule, name string) bool {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fromWhen(t reflect.Type, base []xtag.Rule, when xtag.Rule, visiting map[reflect.Type]bool) (helpers.XObject, error) {
+	args := when.Args
+
+	if len(args) != 3 && len(args) != 4 {
+		return nil, &helpers.TagError{Rule: when.Name, Arg: strings.Join(args, ","), Err: fmt.Errorf("expected 3 or 4 arguments, got %d", len(args))}
+	}
+
+	key, expected := args[0], args[1]
+	branches := make([]helpers.XObject, 2)
+
+	for i, tag := range args[2:] {
+		rules := append(make([]xtag.Rule, 0, len(base)), base...)
+
+		if tag != "" {
+			branchRules, err := xtag.Parse(tag)
+
+			if err != nil {
+				return nil, &helpers.TagError{Rule: when.Name, Arg: strings.Join(args, ","), Err: err}
+			}
+
+			rules = append(rules, branchRules...)
+		}
+
+		xo, err := fromField(t, rules, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		branches[i] = xo
+	}
+
+	if branches[1] == nil {
+		xo, err := fromField(t, base, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		branches[1] = xo
+	}
+
+	condition := WhenFunc(func(values map[string]interface{}) bool {
+		value, ok := values[key]
+		return ok && fmt.Sprint(helpers.Indirect(value)) == expected
+	})
+
+	return condition.Then(branches[0]).Otherwise(branches[1]), nil
+}
+
+func (schema XSchema) validateRoot(val interface{}, values map[string]interface{}) (bool, map[string][]error) {
+	ctx := &helpers.ValidationContext{JSONTags: schema.jsonTags}
+	validationErrors := make(map[string][]error)
+
+	if key, ok := schema.identity(val); ok {
+		ctx.Enter(key)
+	}
+
+	for _, err := range schema.validateMap(values, ctx) {
+		ve := err.(*helpers.ValidationError)
+		validationErrors[ve.Path] = append(validationErrors[ve.Path], ve)
+	}
+
+	return len(validationErrors) == 0, validationErrors
+}
+
+func (schema XSchema) validateMap(values map[string]interface{}, ctx *helpers.ValidationContext) []error {
+	validationErrors := make([]error, 0)
+	unknownKeys := make([]string, 0)
+
+	for _, key := range schema.keys {
+		value, ok := values[key]
+		xo := resolve(schema.values[key], values)
+
+		if xo == nil {
+			continue
+		}
+
+		if !ok {
+			if !schema.ignoreMissing && schema.requires(key, xo) {
+				validationErrors = append(validationErrors, &helpers.ValidationError{Path: key, Rule: "Required", Message: "is required"})
+			}
+
+			continue
+		}
+
+		if isValid, errors := helpers.ValidateContext(xo, value, ctx); !isValid {
+			validationErrors = appendErrors(validationErrors, key, value, errors)
+		}
+	}
+
+	if schema.unknownKeys == Strict {
+		for key := range values {
+			if _, ok := schema.values[key]; !ok {
+				unknownKeys = append(unknownKeys, key)
+			}
+		}
+
+		sort.Strings(unknownKeys)
+
+		for _, key := range unknownKeys {
+			validationErrors = append(validationErrors, &helpers.ValidationError{Path: key, Rule: "UnknownKey", Value: values[key], Message: "invalid key"})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	for _, r := range schema.refinements {
+		if err := r.run(values); err != nil {
+			ve := helpers.WithPath(r.key, values[r.key], err)
+
+			if r.key != "" && ve.Value == nil {
+				ve.Value = values[r.key]
+			}
+
+			validationErrors = append(validationErrors, ve)
+		}
+	}
+
+	return validationErrors
+}
+
+func (r refinement) rename(from string, to string) refinement {
+	aliases := make(map[string]string, len(r.aliases)+1)
+
+	for alias, key := range r.aliases {
+		if key == from {
+			key = to
+		}
+
+		aliases[alias] = key
+	}
+
+	if _, ok := aliases[from]; !ok {
+		aliases[from] = to
+	}
+
+	if r.key == from {
+		r.key = to
+	}
+
+	r.aliases = aliases
+	return r
+}
+
+func (r refinement) run(values map[string]interface{}) error {
+	if len(r.aliases) == 0 {
+		return r.check(values)
+	}
+
+	view := make(map[string]interface{}, len(values)+len(r.aliases))
+
+	for key, value := range values {
+		view[key] = value
+	}
+
+	for alias, key := range r.aliases {
+		delete(view, alias)
+
+		if value, ok := values[key]; ok {
+			view[alias] = value
+		}
+	}
+
+	err := r.check(view)
+	ve, ok := err.(*helpers.ValidationError)
+
+	if !ok {
+		return err
+	}
+
+	head, rest := ve.Path, ""
+
+	if i := strings.IndexAny(head, ".["); i >= 0 {
+		head, rest = head[:i], head[i:]
+	}
+
+	if key, ok := r.aliases[head]; ok {
+		ve = ve.WithValue(ve.Value)
+		ve.Path = key + rest
+	}
+
+	return ve
+}
+
+func When(key string, is helpers.XObject) XWhen {
+	return WhenFunc(func(values map[string]interface{}) bool {
+		value, ok := values[key]
+
+		if !ok {
+			return false
+		}
+
+		isValid, _ := is.Validate(value)
+		return isValid
+	})
+}
+
+func WhenFunc(condition func(values map[string]interface{}) bool) XWhen {
+	return XWhen{condition: condition}
+}
+
+func (xw XWhen) Then(xo helpers.XObject) XWhen {
+	xw.then = xo
+	return xw
+}
+
+func (xw XWhen) Otherwise(xo helpers.XObject) XWhen {
+	xw.otherwise = xo
+	return xw
+}
+
+func (xw XWhen) Validate(val interface{}) (bool, []error) {
+	return xw.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (xw XWhen) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	xo := resolve(xw, nil)
+
+	if xo == nil {
+		return true, nil
+	}
+
+	return helpers.ValidateContext(xo, val, ctx)
+}
+
+func (xw XWhen) String() string {
+	out := "XWhen("
+
+	if xw.then != nil {
+		out += "Then:" + xw.then.String() + ","
+	}
+
+	if xw.otherwise != nil {
+		out += "Otherwise:" + xw.otherwise.String() + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func DiscriminatedUnion(discriminator string) XUnion {
+	xu := XUnion{discriminator: discriminator}
+	xu.branches = make(map[string]XSchema)
+	return xu
+}
+
+func (xu XUnion) Case(value string, schema XSchema) XUnion {
+	branches := make(map[string]XSchema, len(xu.branches)+1)
+
+	for k, v := range xu.branches {
+		branches[k] = v
+	}
+
+	if _, ok := branches[value]; !ok {
+		cases := make([]string, len(xu.cases), len(xu.cases)+1)
+		copy(cases, xu.cases)
+		xu.cases = append(cases, value)
+	}
+
+	branches[value] = schema
+	xu.branches = branches
+	return xu
+}
+
+func (xu XUnion) UseJSONTags() XUnion {
+	xu.jsonTags = true
+	return xu
+}
+
+func (xu XUnion) Optional() XUnion {
+	xu.optional = true
+	return xu
+}
+
+func (xu XUnion) Nullable() XUnion {
+	xu.nullable = true
+	return xu
+}
+
+func (xu XUnion) IsOptional() bool {
+	return xu.optional
+}
+
+func (xu XUnion) IsNullable() bool {
+	return xu.nullable
+}
+
+func (xu XUnion) Required() XUnion {
+	xu.required = true
+	return xu
+}
+
+func (xu XUnion) IsRequired() bool {
+	return xu.required
+}
+
+func (xu XUnion) Validate(val interface{}) (bool, []error) {
+	return xu.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (xu XUnion) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	if helpers.Indirect(val) == nil {
+		if xu.nullable {
+			return true, nil
+		}
+
+		return false, []error{helpers.NullError()}
+	}
+
+	defer ctx.UseJSONTags(xu.jsonTags)()
+
+	values, ok := toMap(val, ctx.JSONTags)
+
+	if !ok {
+		return false, []error{helpers.TypeError("object", val)}
+	}
+
+	discriminator, ok := values[xu.discriminator]
+
+	if !ok {
+		return false, []error{&helpers.ValidationError{Path: xu.discriminator, Rule: "Required", Message: "is required"}}
+	}
+
+	branch, ok := xu.branches[fmt.Sprint(helpers.Indirect(discriminator))]
+
+	if !ok {
+		return false, []error{&helpers.ValidationError{
+			Path:    xu.discriminator,
+			Rule:    "Discriminator",
+			Params:  map[string]interface{}{"values": xu.cases},
+			Value:   discriminator,
+			Message: fmt.Sprintf("unknown discriminator: must be one of: %v", xu.cases),
+		}}
+	}
+
+	return branch.ValidateContext(values, ctx)
+}
+
+func (xu XUnion) String() string {
+	out := "XUnion("
+
+	if xu.optional {
+		out += "Optional,"
+	}
+
+	if xu.nullable {
+		out += "Nullable,"
+	}
+
+	if xu.required {
+		out += "Required,"
+	}
+
+	for _, value := range xu.cases {
+		out += xu.discriminator + "=" + value + ":" + xu.branches[value].String() + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func AnyOf(xos ...helpers.XObject) XComposite {
+	return newComposite("AnyOf", xos)
+}
+
+func AllOf(xos ...helpers.XObject) XComposite {
+	return newComposite("AllOf", xos)
+}
+
+func OneOfSchemas(xos ...helpers.XObject) XComposite {
+	return newComposite("OneOfSchemas", xos)
+}
+
+func Not(xo helpers.XObject) XComposite {
+	return newComposite("Not", []helpers.XObject{xo})
+}
+
+func newComposite(op string, xos []helpers.XObject) XComposite {
+	for i, xo := range xos {
+		if xo == nil {
+			panic(fmt.Sprintf("%s: validator %d is nil", op, i))
+		}
+	}
+
+	return XComposite{op: op, children: xos}
+}
+
+func (xc XComposite) Optional() XComposite {
+	xc.optional = true
+	return xc
+}
+
+func (xc XComposite) Nullable() XComposite {
+	xc.nullable = true
+	return xc
+}
+
+func (xc XComposite) IsOptional() bool {
+	return xc.optional
+}
+
+func (xc XComposite) IsNullable() bool {
+	return xc.nullable
+}
+
+func (xc XComposite) IsRequired() bool {
+	switch xc.op {
+	case "AllOf":
+		for _, child := range xc.children {
+			if isRequired(child) {
+				return true
+			}
+		}
+	case "AnyOf", "OneOfSchemas":
+		for _, child := range xc.children {
+			if !isRequired(child) {
+				return false
+			}
+		}
+
+		return len(xc.children) > 0
+	}
+
+	return false
+}
+
+func (xc XComposite) Validate(val interface{}) (bool, []error) {
+	return xc.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (xc XComposite) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	if xc.nullable && helpers.Indirect(val) == nil {
+		return true, nil
+	}
+
+	validationErrors := make([]error, 0)
+	branchErrors := make([][]error, len(xc.children))
+	matches := make([]int, 0)
+
+	for i, child := range xc.children {
+		if isValid, errors := helpers.ValidateContext(child, val, ctx); isValid {
+			matches = append(matches, i)
+		} else {
+			branchErrors[i] = errors
+		}
+	}
+
+	switch xc.op {
+	case "AllOf":
+		for _, errors := range branchErrors {
+			validationErrors = append(validationErrors, errors...)
+		}
+	case "AnyOf":
+		if len(matches) == 0 {
+			validationErrors = append(validationErrors, branchError(xc.op, "must match at least one of", branchErrors, val))
+		}
+	case "OneOfSchemas":
+		if len(matches) == 0 {
+			validationErrors = append(validationErrors, branchError(xc.op, "must match exactly one of", branchErrors, val))
+		} else if len(matches) > 1 {
+			validationErrors = append(validationErrors, &helpers.ValidationError{
+				Rule:    xc.op,
+				Params:  map[string]interface{}{"matches": matches},
+				Value:   val,
+				Message: fmt.Sprintf("must match exactly one schema, matched %v", matches),
+			})
+		}
+	case "Not":
+		if len(matches) > 0 {
+			validationErrors = append(validationErrors, helpers.NewError(xc.op, nil, "must not match "+xc.children[0].String()).WithValue(val))
+		}
+	}
+
+	return len(validationErrors) == 0, validationErrors
+}
+
+func (xc XComposite) String() string {
+	out := xc.op + "("
+
+	if xc.optional {
+		out += "Optional,"
+	}
+
+	if xc.nullable {
+		out += "Nullable,"
+	}
+
+	for _, child := range xc.children {
+		out += child.String() + ","
+	}
+
+	out += ")"
+
+	return out
+}
+
+func branchError(rule string, message string, branchErrors [][]error, val interface{}) *helpers.ValidationError {
+	reasons := make([]string, 0, len(branchErrors))
+	redacted := make([]helpers.ValidationErrors, 0, len(branchErrors))
+
+	for i, errors := range branchErrors {
+		redacted = append(redacted, helpers.NewValidationErrors(errors).Redact())
+		messages := make([]string, 0, len(errors))
+
+		for _, err := range errors {
+			messages = append(messages, err.Error())
+		}
+
+		reasons = append(reasons, fmt.Sprintf("(%d) %s", i, strings.Join(messages, ", ")))
+	}
+
+	return &helpers.ValidationError{
+		Rule:    rule,
+		Params:  map[string]interface{}{"errors": redacted},
+		Value:   val,
+		Message: message + ": " + strings.Join(reasons, "; "),
+	}
+}
+
+func Lazy(factory func() helpers.XObject) XLazy {
+	return XLazy{maxDepth: DefaultMaxDepth, resolved: &lazyObject{factory: factory}}
+}
+
+func (xl XLazy) MaxDepth(depth int) XLazy {
+	xl.maxDepth = depth
+	return xl
+}
+
+func (xl XLazy) Optional() XLazy {
+	xl.optional = true
+	return xl
+}
+
+func (xl XLazy) Nullable() XLazy {
+	xl.nullable = true
+	return xl
+}
+
+func (xl XLazy) IsOptional() bool {
+	return xl.optional
+}
+
+func (xl XLazy) IsNullable() bool {
+	return xl.nullable
+}
+
+func (xl XLazy) Required() XLazy {
+	xl.required = true
+	return xl
+}
+
+func (xl XLazy) IsRequired() bool {
+	return xl.required
+}
+
+func (xl XLazy) Validate(val interface{}) (bool, []error) {
+	return xl.ValidateContext(val, &helpers.ValidationContext{})
+}
+
+func (xl XLazy) ValidateContext(val interface{}, ctx *helpers.ValidationContext) (bool, []error) {
+	if helpers.Indirect(val) == nil {
+		if xl.nullable {
+			return true, nil
+		}
+
+		return false, []error{helpers.NullError()}
+	}
+
+	if key, ok := identity(xl.resolved, val); ok {
+		if !ctx.Enter(key) {
+			return true, nil
+		}
+
+		defer ctx.Leave(key)
+	}
+
+	if ctx.Depth >= xl.maxDepth {
+		return false, []error{&helpers.ValidationError{
+			Rule:    "MaxDepth",
+			Params:  map[string]interface{}{"max": xl.maxDepth},
+			Value:   val,
+			Message: fmt.Sprintf("must not be nested deeper than %d levels", xl.maxDepth),
+		}}
+	}
+
+	ctx.Depth++
+	defer func() { ctx.Depth-- }()
+
+	return helpers.ValidateContext(xl.object(), val, ctx)
+}
+
+func (xl XLazy) String() string {
+	out := "XLazy("
+
+	if xl.optional {
+		out += "Optional,"
+	}
+
+	if xl.nullable {
+		out += "Nullable,"
+	}
+
+	if xl.required {
+		out += "Required,"
+	}
+
+	out += ")"
+
+	return out
+}
+
+func (xl XLazy) object() helpers.XObject {
+	xl.resolved.once.Do(func() {
+		xl.resolved.xo = xl.resolved.factory()
+	})
+
+	return xl.resolved.xo
+}
+
+// A pointer, map or slice reached again through its own descendants is
+// already being validated further up the path, so a revisit by the same
+// validator is treated as valid and cycles end at the first repeat.
+func identity(validator interface{}, val interface{}) (visitKey, bool) {
+	rv := reflect.ValueOf(val)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map:
+		return visitKey{validator: validator, t: rv.Type(), ptr: rv.Pointer()}, true
+	case reflect.Slice:
+		return visitKey{validator: validator, t: rv.Type(), ptr: rv.Pointer(), n: rv.Len()}, true
+	}
+
+	return visitKey{}, false
+}
+
+func (schema XSchema) identity(val interface{}) (visitKey, bool) {
+	return identity(schemaKey{
+		values:        reflect.ValueOf(schema.values).Pointer(),
+		presence:      reflect.ValueOf(schema.presence).Pointer(),
+		refinements:   reflect.ValueOf(schema.refinements).Pointer(),
+		jsonTags:      schema.jsonTags,
+		ignoreMissing: schema.ignoreMissing,
+		unknownKeys:   schema.unknownKeys,
+	}, val)
+}
+
+func resolve(xo helpers.XObject, values map[string]interface{}) helpers.XObject {
+	for {
+		xw, ok := xo.(XWhen)
+
+		if !ok {
+			return xo
+		}
+
+		if xw.condition(values) {
+			xo = xw.then
+		} else {
+			xo = xw.otherwise
+		}
+	}
+}
+
+func (schema XSchema) requires(key string, xo helpers.XObject) bool {
+	if required, ok := schema.presence[key]; ok {
+		return required
+	}
+
+	return !isOptional(xo) && isRequired(xo)
+}
+
+func isOptional(xo helpers.XObject) bool {
+	if xopt, ok := xo.(helpers.XOptional); ok {
+		return xopt.IsOptional()
+	}
+
+	return false
+}
+
+func isRequired(xo helpers.XObject) bool {
+	if xr, ok := xo.(helpers.XRequirer); ok {
+		return xr.IsRequired()
+	}
+
+	return false
+}
+
+func appendErrors(validationErrors []error, key string, value interface{}, errors []error) []error {
+	for _, err := range errors {
+		validationErrors = append(validationErrors, helpers.WithPath(key, value, err))
+	}
+
+	return validationErrors
+}
+
+func toMap(val interface{}, jsonTags bool) (map[string]interface{}, bool) {
+	if values, ok := val.(map[string]interface{}); ok {
+		return values, true
+	}
+
+	if val == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(val)
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		values := make(map[string]interface{})
+		structToMap(rv, jsonTags, values)
+		return values, true
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		values := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+
+		for iter.Next() {
+			values[iter.Key().String()] = fieldValue(iter.Value())
+		}
+
+		return values, true
+	}
+
+	return nil, false
+}
+
+func structToMap(rv reflect.Value, jsonTags bool, values map[string]interface{}) {
+	rt := rv.Type()
+	promoted := make(map[string]interface{})
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Name
+
+		if jsonTags {
+			tag := field.Tag.Get("json")
+
+			if tag == "-" {
+				continue
+			}
+
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+
+		if field.Anonymous && name == field.Name {
+			embedded := rv.Field(i)
+
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				structToMap(embedded, jsonTags, promoted)
+				continue
+			}
+		}
+
+		if !field.IsExported() || !rv.Field(i).CanInterface() {
+			continue
+		}
+
+		values[name] = fieldValue(rv.Field(i))
+	}
+
+	for name, value := range promoted {
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
+}
+
+func fieldValue(rv reflect.Value) interface{} {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+
+		if rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Struct {
+			break
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv.Interface()
+}
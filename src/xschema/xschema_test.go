@@ -1,17 +1,79 @@
 package xschema_test
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"regexp"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xarray"
+	"github.com/radchukd/go-xschema/src/xbool"
+	"github.com/radchukd/go-xschema/src/xfloat"
 	"github.com/radchukd/go-xschema/src/xnumber"
 	"github.com/radchukd/go-xschema/src/xschema"
 	"github.com/radchukd/go-xschema/src/xstring"
 )
 
-func TestClone(t *testing.T) {}
+func TestClone(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Name", xstring.Create().Required())
+
+	clone := xschema.Clone(schema).
+		AddNumber("Age", xnumber.Create().Gte(18))
+
+	values := map[string]interface{}{"Name": "John", "Age": 1}
+
+	if isValid, _ := schema.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> false; want true", values)
+	}
+
+	if isValid, _ := clone.ValidateMap(values); isValid {
+		t.Errorf("ValidateMap(%v) -> true; want false", values)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s1 := xschema.Create().
+		AddString("Name", xstring.Create().Required())
+	s2 := xschema.Create().
+		AddNumber("Age", xnumber.Create().Gte(18))
+
+	schema := xschema.Merge(s1, s2)
+	values := map[string]interface{}{"Name": "", "Age": 1}
+
+	if _, errs := schema.ValidateMap(values); len(errs) != 2 {
+		t.Errorf("ValidateMap(%v) -> %v; want 2 errors", values, errs)
+	}
+
+	if _, errs := s1.ValidateMap(values); len(errs) != 1 {
+		t.Errorf("ValidateMap(%v) -> %v; want 1 error", values, errs)
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	base := xschema.Create().
+		AddString("Name", xstring.Create().Required())
+	a := base.AddNumber("Age", xnumber.Create().Gte(18))
+	b := base.AddNumber("Age", xnumber.Create().Gte(21))
+
+	values := map[string]interface{}{"Name": "John", "Age": 19}
+
+	if isValid, _ := a.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> false; want true", values)
+	}
+
+	if isValid, _ := b.ValidateMap(values); isValid {
+		t.Errorf("ValidateMap(%v) -> true; want false", values)
+	}
 
-func TestMerge(t *testing.T) {}
+	if out := base.String(); out != "XSchema(Name:XString(Required,),)" {
+		t.Errorf("String() -> %s; want XSchema(Name:XString(Required,),)", out)
+	}
+}
 
 func TestValidateKey(t *testing.T) {
 	var value string
@@ -147,14 +209,90 @@ func TestSValidateStruct(t *testing.T) {
 	}
 }
 
+func TestValidateStructFields(t *testing.T) {
+	type Audit struct {
+		CreatedBy string
+	}
+
+	type User struct {
+		Audit
+		FirstName string  `json:"first_name"`
+		Nickname  *string `json:"nickname,omitempty"`
+		Password  string  `json:"-"`
+		internal  string
+	}
+
+	nickname := "Johnny"
+	schema := xschema.Create().
+		AddString("FirstName", xstring.Create().Required()).
+		AddString("Nickname", xstring.Create().Min(3)).
+		AddString("Password", xstring.Create().Min(8)).
+		AddString("CreatedBy", xstring.Create().Required())
+
+	value := User{Audit{"admin"}, "John", &nickname, "secret123", ""}
+
+	if isValid, errs := schema.SValidateStruct(value); !isValid {
+		t.Errorf("SValidateStruct(%v) -> %v; want true", value, errs)
+	}
+
+	value.Password = "short"
+
+	if isValid, _ := schema.ValidateStruct(&value); isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	schema = xschema.Create().UseJSONTags().
+		AddString("first_name", xstring.Create().Required()).
+		AddString("nickname", xstring.Create().Min(3)).
+		AddString("CreatedBy", xstring.Create().Required())
+
+	if isValid, errs := schema.SValidateStruct(value); !isValid {
+		t.Errorf("SValidateStruct(%v) -> %v; want true", value, errs)
+	}
+
+	if isValid, _ := schema.ValidateStruct(123); isValid {
+		t.Errorf("ValidateStruct(123) -> true; want false")
+	}
+
+	type Address struct {
+		Zip string `json:"zip"`
+	}
+
+	type Customer struct {
+		Addresses []Address `json:"addresses"`
+		Billing   *Address  `json:"billing"`
+	}
+
+	address := xschema.Create().AddString("zip", xstring.Create().Required())
+	schema = xschema.Create().UseJSONTags().
+		AddArray("addresses", xarray.Create().Of(address)).
+		AddObject("billing", address)
+
+	customer := Customer{Addresses: []Address{{"123"}}, Billing: &Address{"456"}}
+
+	if isValid, errs := schema.ValidateStruct(customer); !isValid {
+		t.Errorf("ValidateStruct(%v) -> %v; want true", customer, errs)
+	}
+
+	customer.Billing.Zip = ""
+	_, errs := schema.ValidateStruct(customer)
+
+	if len(errs) != 1 || errs["billing.zip"] == nil {
+		t.Errorf("ValidateStruct(%v) -> %v; want error on billing.zip", customer, errs)
+	}
+}
+
 func TestValidateTaggedStruct(t *testing.T) {
 	type User struct {
-		FirstName string `x:"Required,Min=3,Pattern=^[A-Z]{1}[a-z]+$"`
-		LastName  string `x:"Required,Min=3,Pattern=^[A-Z]{1}[a-z]+$"`
-		Age       uint8  `x:"Required,Gte=18"`
+		FirstName string   `x:"Required,Min=3,Pattern=^[A-Z]{1}[a-z]+$"`
+		LastName  string   `x:"Required,Min=3,Pattern=^[A-Z]{1}[a-z]+$"`
+		Age       uint8    `x:"Required,Gte=18"`
+		Roles     []string `x:"MinItems=1,Unique"`
+		Terms     bool     `x:"Required"`
+		Height    float64  `x:"Gt=0.5"`
 	}
 
-	value := User{"John", "doe", 0}
+	value := User{"John", "doe", 0, []string{"admin"}, true, 1.8}
 
 	if isValid, _ := xschema.ValidateTaggedStruct(value); isValid {
 		t.Errorf("ValidateTaggedStruct(%v) -> true; want false", value)
@@ -166,4 +304,1391 @@ func TestValidateTaggedStruct(t *testing.T) {
 	if isValid, _ := xschema.ValidateTaggedStruct(value); !isValid {
 		t.Errorf("ValidateTaggedStruct(%v) -> false; want true", value)
 	}
+
+	value.Roles = []string{"admin", "admin"}
+
+	if isValid, _ := xschema.ValidateTaggedStruct(value); isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> true; want false", value)
+	}
+}
+
+func TestAddObject(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  string
+	}
+
+	type User struct {
+		Name    string
+		Address Address
+	}
+
+	schema := xschema.Create().
+		AddString("Name", xstring.Create().Required()).
+		AddObject("Address", xschema.Create().
+			AddString("City", xstring.Create().Required()).
+			AddString("Zip", xstring.Create().Length(5)))
+
+	value := User{"John", Address{"Kyiv", "123"}}
+
+	isValid, errs := schema.ValidateStruct(value)
+
+	if isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	if _, ok := errs["Address.Zip"]; !ok {
+		t.Errorf("ValidateStruct(%v) -> %v; want key Address.Zip", value, errs)
+	}
+
+	value.Address.Zip = "01001"
+
+	if isValid, _ := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> false; want true", value)
+	}
+
+	values := map[string]interface{}{
+		"Name":    "John",
+		"Address": map[string]interface{}{"City": "", "Zip": "01001"},
+	}
+
+	if isValid, _ := schema.ValidateMap(values); isValid {
+		t.Errorf("ValidateMap(%v) -> true; want false", values)
+	}
+
+	values["Address"] = "Kyiv"
+
+	if isValid, _ := schema.ValidateMap(values); isValid {
+		t.Errorf("ValidateMap(%v) -> true; want false", values)
+	}
+}
+
+func TestAddArray(t *testing.T) {
+	type User struct {
+		Name string
+		Tags []string
+	}
+
+	schema := xschema.Create().
+		AddArray("Tags", xarray.Create().MinItems(1).Of(xstring.Create().Required()))
+
+	value := User{"John", []string{"admin", ""}}
+
+	isValid, errs := schema.ValidateStruct(value)
+
+	if isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	if _, ok := errs["Tags[1]"]; !ok {
+		t.Errorf("ValidateStruct(%v) -> %v; want key Tags[1]", value, errs)
+	}
+
+	value.Tags = []string{"admin"}
+
+	if isValid, _ := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> false; want true", value)
+	}
+}
+
+func TestAddBool(t *testing.T) {
+	type User struct {
+		Name   string
+		Active bool
+	}
+
+	schema := xschema.Create().
+		AddBool("Active", xbool.Create().MustBe(true))
+
+	value := User{"John", false}
+
+	if isValid, _ := schema.ValidateStruct(value); isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	value.Active = true
+
+	if isValid, _ := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> false; want true", value)
+	}
+}
+
+func TestAddFloat(t *testing.T) {
+	type Product struct {
+		Name  string
+		Price float64
+	}
+
+	schema := xschema.Create().
+		AddFloat("Price", xfloat.Create().Gt(0).MultipleOf(0.01))
+
+	value := Product{"Tea", 4.995}
+
+	if isValid, _ := schema.ValidateStruct(value); isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	value.Price = 4.99
+
+	if isValid, _ := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> false; want true", value)
+	}
+}
+
+func TestValidateStructPrecision(t *testing.T) {
+	type Order struct {
+		ID uint64
+	}
+
+	schema := xschema.Create().
+		AddNumber("ID", xnumber.Create().OneOf([]int{9007199254740993}))
+
+	value := Order{9007199254740993}
+
+	if isValid, _ := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> false; want true", value)
+	}
+
+	value.ID = 9007199254740992
+
+	if isValid, _ := schema.ValidateStruct(value); isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	schema := xschema.Create().
+		AddObject("User", xschema.Create().
+			AddArray("Emails", xarray.Create().Of(xstring.Create().Email())).
+			AddString("Password", xstring.Create().Min(8)))
+
+	values := map[string]interface{}{
+		"User": map[string]interface{}{
+			"Emails":   []interface{}{"a@example.com", "b@example.com", "invalid"},
+			"Password": "secret",
+		},
+	}
+
+	_, errs := schema.ValidateMap(values)
+
+	if len(errs["User.Emails[2]"]) != 1 {
+		t.Fatalf("ValidateMap(%v) -> %v; want key User.Emails[2]", values, errs)
+	}
+
+	ve := errs["User.Password"][0].(*helpers.ValidationError)
+
+	if ve.Rule != "Min" || ve.Params["min"] != 8 || ve.Value != "secret" {
+		t.Errorf("ValidateMap(%v) -> %+v; want Min rule with params", values, ve)
+	}
+
+	if ve.Redact().Value != nil {
+		t.Errorf("Redact() -> %v; want nil", ve.Redact().Value)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	schema := xschema.Create().
+		AddString("LastName", xstring.Create().Required()).
+		AddString("FirstName", xstring.Create().Required()).
+		AddNumber("Age", xnumber.Create().Gte(18))
+
+	if out := schema.String(); out != "XSchema(LastName:XString(Required,),FirstName:XString(Required,),Age:XNumber(Gte,),)" {
+		t.Errorf("String() -> %s", out)
+	}
+
+	values := map[string]interface{}{"Age": 1, "FirstName": "", "LastName": ""}
+
+	for i := 0; i < 10; i++ {
+		_, errs := schema.Validate(values)
+
+		if len(errs) != 3 ||
+			errs[0].(*helpers.ValidationError).Path != "LastName" ||
+			errs[1].(*helpers.ValidationError).Path != "FirstName" ||
+			errs[2].(*helpers.ValidationError).Path != "Age" {
+			t.Fatalf("Validate(%v) -> %v; want [LastName FirstName Age]", values, errs)
+		}
+	}
+}
+
+func TestMissingKeys(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Name", xstring.Create().Required()).
+		AddString("Nickname", xstring.Create().Min(3))
+
+	values := map[string]interface{}{}
+
+	isValid, errs := schema.ValidateMap(values)
+
+	if isValid || len(errs) != 1 || errs["Name"][0].(*helpers.ValidationError).Rule != "Required" {
+		t.Errorf("ValidateMap(%v) -> %v; want Name required", values, errs)
+	}
+
+	if isValid, _ := schema.IgnoreMissing().ValidateMap(values); !isValid {
+		t.Errorf("IgnoreMissing().ValidateMap(%v) -> false; want true", values)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Name", xstring.Create().Required())
+
+	values := map[string]interface{}{"Name": "John", "Role": "admin"}
+
+	if isValid, _ := schema.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> false; want true", values)
+	}
+
+	isValid, errs := schema.UnknownKeys(xschema.Strict).ValidateMap(values)
+
+	if isValid || errs["Role"][0].(*helpers.ValidationError).Rule != "UnknownKey" {
+		t.Errorf("UnknownKeys(Strict).ValidateMap(%v) -> %v; want Role unknown", values, errs)
+	}
+
+	parsed, isValid, _ := schema.UnknownKeys(xschema.Strip).Parse(values)
+
+	if _, ok := parsed["Role"]; !isValid || ok || parsed["Name"] != "John" {
+		t.Errorf("UnknownKeys(Strip).Parse(%v) -> %v; want Role stripped", values, parsed)
+	}
+
+	parsed, _, _ = schema.Parse(values)
+
+	if _, ok := parsed["Role"]; !ok {
+		t.Errorf("Parse(%v) -> %v; want Role kept", values, parsed)
+	}
+}
+
+func TestOptionalNullable(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Name", xstring.Create().Required().Optional()).
+		AddString("Email", xstring.Create().Email().Nullable()).
+		AddNumber("Age", xnumber.Create().Required())
+
+	values := map[string]interface{}{"Age": nil}
+
+	_, errs := schema.ValidateMap(values)
+
+	if len(errs) != 1 || errs["Age"][0].(*helpers.ValidationError).Rule != "NotNull" {
+		t.Errorf("ValidateMap(%v) -> %v; want Age not null", values, errs)
+	}
+
+	values = map[string]interface{}{"Email": nil, "Age": 18}
+
+	if isValid, errs := schema.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> %v; want true", values, errs)
+	}
+
+	values["Name"] = ""
+
+	if isValid, _ := schema.ValidateMap(values); isValid {
+		t.Errorf("ValidateMap(%v) -> true; want false", values)
+	}
+
+	type Patch struct {
+		Email *string
+		Age   int
+	}
+
+	value := Patch{nil, 18}
+
+	if isValid, errs := schema.ValidateStruct(value); !isValid {
+		t.Errorf("ValidateStruct(%v) -> %v; want true", value, errs)
+	}
+}
+
+func TestValidateTaggedStructTypes(t *testing.T) {
+	type Email string
+
+	type Item struct {
+		SKU string `x:"Required,Length=6"`
+	}
+
+	type Address struct {
+		City string `x:"Required"`
+	}
+
+	type Order struct {
+		Email    Email    `x:"Email"`
+		Note     *string  `x:"Min=3"`
+		Address  Address  `x:"Optional"`
+		Items    []Item   `x:"MinItems=1"`
+		Discount *float64 `x:"Nullable,Lt=1"`
+	}
+
+	note := "ok"
+	value := Order{"john@example.com", &note, Address{""}, []Item{{"ABC123"}, {"ABC"}}, nil}
+
+	isValid, errs := xschema.ValidateTaggedStruct(value)
+
+	if isValid || len(errs) != 3 || errs["Note"] == nil || errs["Address.City"] == nil || errs["Items[1].SKU"] == nil {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want Note, Address.City and Items[1].SKU", value, errs)
+	}
+
+	note = "fine"
+	value.Address.City = "Kyiv"
+	value.Items = value.Items[:1]
+
+	if isValid, errs := xschema.ValidateTaggedStruct(value); !isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want true", value, errs)
+	}
+
+	type Profile struct {
+		Name     *string  `x:"Required"`
+		Age      *int     `x:"Required,Gte=18"`
+		Nickname *string  `x:"Min=3"`
+		Contact  *string  `x:"Optional,Email"`
+		Address  *Address `x:"Required"`
+		Billing  *Address `x:"Nullable"`
+	}
+
+	_, errs = xschema.ValidateTaggedStruct(Profile{})
+
+	if len(errs) != 4 || errs["Name"] == nil || errs["Age"] == nil || errs["Nickname"] == nil || errs["Address"] == nil {
+		t.Errorf("ValidateTaggedStruct(Profile{}) -> %v; want errors on Name, Age, Nickname and Address", errs)
+	}
+
+	name, age, nickname := "John", 18, "johnny"
+	profile := Profile{Name: &name, Age: &age, Nickname: &nickname, Address: &Address{"Kyiv"}}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(profile); !isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want true", profile, errs)
+	}
+
+	type Unsupported struct {
+		Events chan string `x:"Required"`
+	}
+
+	isValid, errs = xschema.ValidateTaggedStruct(Unsupported{})
+
+	if isValid || errs["Events"] == nil || errs["Events"][0].(*helpers.ValidationError).Rule != "Tag" {
+		t.Errorf("ValidateTaggedStruct(Unsupported) -> %v; want Events unsupported type", errs)
+	}
+}
+
+func TestValidateTaggedStructNested(t *testing.T) {
+	type Pagination struct {
+		Page  int `x:"Gte=1"`
+		Limit int `x:"Gte=1,Lte=100"`
+	}
+
+	type audit struct {
+		CreatedBy string `x:"Required"`
+	}
+
+	type Filter struct {
+		Query string `x:"Min=2"`
+	}
+
+	type Category struct {
+		Name   string    `x:"Required"`
+		Parent *Category `x:"Optional"`
+	}
+
+	type Request struct {
+		Pagination
+		*audit
+		Filter   Filter
+		Owner    *Filter
+		Category Category
+	}
+
+	value := &Request{Pagination{0, 10}, &audit{""}, Filter{"a"}, &Filter{"b"}, Category{"Books", nil}}
+
+	isValid, errs := xschema.ValidateTaggedStruct(value)
+
+	if isValid || len(errs) != 4 || errs["Page"] == nil || errs["CreatedBy"] == nil || errs["Filter.Query"] == nil || errs["Owner.Query"] == nil {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want Page, CreatedBy, Filter.Query and Owner.Query", value, errs)
+	}
+
+	value.Page = 1
+	value.audit.CreatedBy = "admin"
+	value.Filter.Query = "ab"
+	value.Owner = nil
+
+	if isValid, errs := xschema.ValidateTaggedStruct(value); !isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want true", value, errs)
+	}
+
+	var nilValue *Request
+
+	if isValid, _ := xschema.ValidateTaggedStruct(nilValue); isValid {
+		t.Errorf("ValidateTaggedStruct(nil) -> true; want false")
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	type User struct {
+		Name  string `x:"Required,Min=2"`
+		Email string `x:"Email"`
+	}
+
+	schema, err := xschema.SchemaFor[User]()
+
+	if err != nil {
+		t.Fatalf("SchemaFor[User]() -> %v; want nil error", err)
+	}
+
+	value := User{"J", "john@example.com"}
+
+	if isValid, _ := schema.ValidateStruct(value); isValid {
+		t.Errorf("ValidateStruct(%v) -> true; want false", value)
+	}
+
+	pointerSchema, err := xschema.CompileTagged(reflect.TypeOf(&value))
+
+	if err != nil || pointerSchema.String() != schema.String() {
+		t.Errorf("CompileTagged(*User) -> %s, %v; want %s", pointerSchema.String(), err, schema.String())
+	}
+
+	if _, err := xschema.SchemaFor[string](); err == nil {
+		t.Errorf("SchemaFor[string]() -> nil error; want error")
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if isValid, _ := xschema.ValidateTaggedStruct(User{"John", "john@example.com"}); !isValid {
+				t.Errorf("ValidateTaggedStruct() -> false; want true")
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestTagErrors(t *testing.T) {
+	type Address struct {
+		Zip string `x:"Length=five"`
+	}
+
+	type User struct {
+		Name    string `x:"Requried"`
+		Address Address
+	}
+
+	_, err := xschema.SchemaFor[User]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Field != "Name" || te.Rule != "Requried" {
+		t.Errorf("SchemaFor[User]() -> %v; want tag error on Name", err)
+	}
+
+	_, err = xschema.SchemaFor[struct{ Address Address }]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Field != "Address.Zip" || te.Arg != "five" {
+		t.Errorf("SchemaFor() -> %v; want tag error on Address.Zip", err)
+	}
+
+	isValid, errs := xschema.ValidateTaggedStruct(User{})
+
+	if isValid || errs["Name"] == nil {
+		t.Errorf("ValidateTaggedStruct(User) -> %v; want tag error on Name", errs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustSchemaFor[User]() did not panic")
+		}
+	}()
+
+	xschema.MustSchemaFor[User]()
+}
+
+func TestTagGrammar(t *testing.T) {
+	type Item struct {
+		Code  string   `x:"Required,Pattern=^[a-z]{1,3}$"`
+		Sep   string   `x:"StartsWith='a\\,b=c'"`
+		Color string   `x:"OneOf(red, green, blue)"`
+		Size  int      `x:"OneOf=[1,2,3]"`
+		Tags  []string `x:"MaxItems=2,Optional"`
+	}
+
+	schema, err := xschema.SchemaFor[Item]()
+
+	if err != nil {
+		t.Fatalf("SchemaFor[Item]() -> %v; want nil", err)
+	}
+
+	valid := Item{Code: "abc", Sep: "a,b=cd", Color: "green", Size: 2}
+
+	if isValid, errs := schema.Validate(valid); !isValid {
+		t.Errorf("Validate(%v) -> %v; want true", valid, errs)
+	}
+
+	invalid := Item{Code: "abcd", Sep: "a", Color: "pink", Size: 4}
+
+	if _, errs := schema.Validate(invalid); len(errs) != 4 {
+		t.Errorf("Validate(%v) -> %v; want 4 errors", invalid, errs)
+	}
+
+	_, err = xschema.SchemaFor[struct {
+		Code string `x:"Pattern='^a"`
+	}]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Field != "Code" {
+		t.Errorf("SchemaFor() -> %v; want tag error on Code", err)
+	}
+}
+
+func TestTagRegisteredRule(t *testing.T) {
+	codes := map[string]bool{"UA": true, "PL": true}
+	err := xstring.RegisterRule("CountryCode", func(args []string) (helpers.XValidation[string], error) {
+		return helpers.XValidation[string]{
+			E: helpers.NewError("CountryCode", nil, "must be a country code"),
+			F: func(val string) bool { return codes[val] },
+		}, helpers.NoTagArgs(args)
+	})
+
+	if err != nil {
+		t.Fatalf("RegisterRule(CountryCode) -> %v; want nil", err)
+	}
+
+	type Address struct {
+		Country string `x:"Required,CountryCode"`
+	}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(Address{Country: "UA"}); !isValid {
+		t.Errorf("ValidateTaggedStruct(UA) -> %v; want true", errs)
+	}
+
+	isValid, errs := xschema.ValidateTaggedStruct(Address{Country: "XX"})
+
+	if isValid || len(errs["Country"]) != 1 || errs["Country"][0].(*helpers.ValidationError).Rule != "CountryCode" {
+		t.Errorf("ValidateTaggedStruct(XX) -> %v; want CountryCode error", errs)
+	}
+}
+
+func TestTagLateRegisteredRule(t *testing.T) {
+	type Account struct {
+		Handle string `x:"Required,LateHandle"`
+	}
+
+	if _, err := xschema.SchemaFor[Account](); !errors.Is(err, helpers.ErrUnknownRule) {
+		t.Fatalf("SchemaFor[Account]() -> %v; want unknown rule", err)
+	}
+
+	err := xstring.RegisterRule("LateHandle", func(args []string) (helpers.XValidation[string], error) {
+		return helpers.XValidation[string]{F: func(val string) bool { return val[0] == '@' }}, helpers.NoTagArgs(args)
+	})
+
+	if err != nil {
+		t.Fatalf("RegisterRule(LateHandle) -> %v; want nil", err)
+	}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(Account{Handle: "@john"}); !isValid {
+		t.Errorf("ValidateTaggedStruct(@john) -> %v; want true", errs)
+	}
+
+	if _, errs := xschema.ValidateTaggedStruct(Account{Handle: "john"}); !hasRule(errs, "LateHandle") {
+		t.Errorf("ValidateTaggedStruct(john) -> %v; want LateHandle error", errs)
+	}
+}
+
+func TestRefine(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Password", xstring.Create().Required().Min(8)).
+		AddString("PasswordConfirm", xstring.Create().Required()).
+		RefineKey("PasswordConfirm", "EqualTo", func(values map[string]interface{}) bool {
+			return values["Password"] == values["PasswordConfirm"]
+		}, "must match Password")
+
+	values := map[string]interface{}{"Password": "secret123", "PasswordConfirm": "secret123"}
+
+	if isValid, errs := schema.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> %v; want true", values, errs)
+	}
+
+	values["PasswordConfirm"] = "secret321"
+	isValid, errs := schema.ValidateMap(values)
+
+	if ve, ok := errs["PasswordConfirm"][0].(*helpers.ValidationError); isValid || len(errs) != 1 || !ok || ve.Rule != "EqualTo" || ve.Value != "secret321" {
+		t.Errorf("ValidateMap(%v) -> %v; want EqualTo error on PasswordConfirm", values, errs)
+	}
+
+	values["Password"] = "short"
+
+	if _, errs := schema.ValidateMap(values); len(errs) != 1 || errs["Password"] == nil {
+		t.Errorf("ValidateMap(%v) -> %v; want only the Min error on Password", values, errs)
+	}
+
+	nested := xschema.Create().AddObject("Account", schema)
+	_, errs = nested.ValidateMap(map[string]interface{}{
+		"Account": map[string]interface{}{"Password": "secret123", "PasswordConfirm": "other"},
+	})
+
+	if errs["Account.PasswordConfirm"] == nil {
+		t.Errorf("ValidateMap() -> %v; want error on Account.PasswordConfirm", errs)
+	}
+}
+
+func TestRefineStruct(t *testing.T) {
+	type Booking struct {
+		StartDate time.Time
+		EndDate   time.Time
+	}
+
+	schema := xschema.Create().
+		Refine("DateRange", func(values map[string]interface{}) bool {
+			return values["EndDate"].(time.Time).After(values["StartDate"].(time.Time))
+		}, "EndDate must be after StartDate")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	booking := Booking{StartDate: start, EndDate: start.AddDate(0, 0, 7)}
+
+	if isValid, errs := schema.ValidateStruct(booking); !isValid {
+		t.Errorf("ValidateStruct(%v) -> %v; want true", booking, errs)
+	}
+
+	booking.EndDate = start.AddDate(0, 0, -1)
+
+	if _, errs := schema.ValidateStruct(booking); len(errs[""]) != 1 || errs[""][0].Error() != "EndDate must be after StartDate" {
+		t.Errorf("ValidateStruct(%v) -> %v; want DateRange error", booking, errs)
+	}
+}
+
+func TestCustom(t *testing.T) {
+	schema := xschema.Create().
+		AddNumber("Min", xnumber.Create()).
+		AddNumber("Max", xnumber.Create()).
+		Custom("Range", func(values map[string]interface{}) error {
+			if values["Min"].(int) > values["Max"].(int) {
+				return helpers.WithPath("Max", values["Max"], fmt.Errorf("must be at least %v", values["Min"]))
+			}
+			return nil
+		})
+
+	_, errs := schema.ValidateMap(map[string]interface{}{"Min": 5, "Max": 3})
+
+	if ve, ok := errs["Max"][0].(*helpers.ValidationError); !ok || ve.Rule != "Range" || ve.Message != "must be at least 5" || ve.Value != 3 {
+		t.Errorf("ValidateMap() -> %v; want Range error on Max", errs)
+	}
+
+	merged := xschema.Merge(xschema.Create(), schema)
+
+	if isValid, _ := merged.ValidateMap(map[string]interface{}{"Min": 5, "Max": 3}); isValid {
+		t.Errorf("Merge() dropped the Range refinement")
+	}
+}
+
+func TestWhen(t *testing.T) {
+	schema := xschema.Create().
+		AddString("PaymentMethod", xstring.Create().Required().OneOf([]string{"card", "cash"})).
+		AddWhen("CardNumber", xschema.When("PaymentMethod", xstring.Create().OneOf([]string{"card"})).
+			Then(xstring.Create().Required().Length(16)).
+			Otherwise(xstring.Create().Max(0)))
+
+	cases := []struct {
+		values map[string]interface{}
+		errs   int
+	}{
+		{map[string]interface{}{"PaymentMethod": "card", "CardNumber": "4242424242424242"}, 0},
+		{map[string]interface{}{"PaymentMethod": "card"}, 1},
+		{map[string]interface{}{"PaymentMethod": "card", "CardNumber": "42"}, 1},
+		{map[string]interface{}{"PaymentMethod": "cash"}, 0},
+		{map[string]interface{}{"PaymentMethod": "cash", "CardNumber": "4242424242424242"}, 1},
+	}
+
+	for _, c := range cases {
+		if _, errs := schema.ValidateMap(c.values); len(errs) != c.errs {
+			t.Errorf("ValidateMap(%v) -> %v; want %d errors", c.values, errs, c.errs)
+		}
+	}
+
+	type Payment struct {
+		PaymentMethod string
+		CardNumber    string
+	}
+
+	payment := Payment{PaymentMethod: "cash", CardNumber: "4242424242424242"}
+
+	if _, errs := schema.ValidateStruct(payment); errs["CardNumber"] == nil {
+		t.Errorf("ValidateStruct(%v) -> %v; want error on CardNumber", payment, errs)
+	}
+
+	conditional := xschema.Create().AddWhen("Reason", xschema.WhenFunc(func(values map[string]interface{}) bool {
+		return values["Status"] == "rejected"
+	}).Then(xstring.Create().Required()))
+
+	if isValid, _ := conditional.ValidateMap(map[string]interface{}{"Status": "approved"}); !isValid {
+		t.Errorf("WhenFunc(approved) -> false; want true")
+	}
+
+	if isValid, _ := conditional.ValidateMap(map[string]interface{}{"Status": "rejected"}); isValid {
+		t.Errorf("WhenFunc(rejected) -> true; want false")
+	}
+}
+
+func TestWhenTags(t *testing.T) {
+	type Payment struct {
+		PaymentMethod string `x:"Required,OneOf(card,cash)"`
+		CardNumber    string `x:"When(PaymentMethod,card,'Required,Length=16','Max=0')"`
+		Note          string `x:"Max=20,When(PaymentMethod,cash,Required)"`
+	}
+
+	cases := []struct {
+		payment Payment
+		errs    []string
+	}{
+		{Payment{PaymentMethod: "card", CardNumber: "4242424242424242"}, nil},
+		{Payment{PaymentMethod: "card"}, []string{"CardNumber"}},
+		{Payment{PaymentMethod: "cash", Note: "paid in cash"}, nil},
+		{Payment{PaymentMethod: "cash", CardNumber: "42"}, []string{"CardNumber", "Note"}},
+	}
+
+	for _, c := range cases {
+		_, errs := xschema.ValidateTaggedStruct(c.payment)
+
+		if len(errs) != len(c.errs) {
+			t.Errorf("ValidateTaggedStruct(%+v) -> %v; want errors on %v", c.payment, errs, c.errs)
+		}
+
+		for _, key := range c.errs {
+			if errs[key] == nil {
+				t.Errorf("ValidateTaggedStruct(%+v) -> %v; want error on %s", c.payment, errs, key)
+			}
+		}
+	}
+
+	_, err := xschema.SchemaFor[struct {
+		Code string `x:"When(Kind,a)"`
+	}]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Rule != "When" {
+		t.Errorf("SchemaFor() -> %v; want tag error for When", err)
+	}
+}
+
+func TestDiscriminatedUnion(t *testing.T) {
+	union := xschema.DiscriminatedUnion("type").
+		Case("click", xschema.Create().
+			AddNumber("x", xnumber.Create().Required()).
+			AddNumber("y", xnumber.Create().Required())).
+		Case("keypress", xschema.Create().
+			AddString("key", xstring.Create().Required().Length(1)))
+
+	if out := union.String(); out != "XUnion(type=click:XSchema(x:XNumber(Required,),y:XNumber(Required,),),type=keypress:XSchema(key:XString(Required,Length,),),)" {
+		t.Errorf("String() -> %s", out)
+	}
+
+	event := map[string]interface{}{"type": "click", "x": 1, "y": 2}
+
+	if isValid, errs := union.Validate(event); !isValid {
+		t.Errorf("Validate(%v) -> %v; want true", event, errs)
+	}
+
+	event = map[string]interface{}{"type": "keypress", "key": "ab", "x": "1"}
+
+	if _, errs := union.Validate(event); len(errs) != 1 || errs[0].(*helpers.ValidationError).Path != "key" {
+		t.Errorf("Validate(%v) -> %v; want only the keypress error", event, errs)
+	}
+
+	event = map[string]interface{}{"type": "scroll"}
+	_, errs := union.Validate(event)
+
+	if ve, ok := errs[0].(*helpers.ValidationError); len(errs) != 1 || !ok || ve.Rule != "Discriminator" || ve.Path != "type" || ve.Value != "scroll" {
+		t.Errorf("Validate(%v) -> %v; want unknown discriminator", event, errs)
+	}
+
+	if _, errs := union.Validate(map[string]interface{}{}); len(errs) != 1 || errs[0].Error() != "type: is required" {
+		t.Errorf("Validate({}) -> %v; want [type: is required]", errs)
+	}
+
+	type Keypress struct {
+		Type string `json:"type"`
+		Key  string `json:"key"`
+	}
+
+	if isValid, errs := union.UseJSONTags().Validate(Keypress{Type: "keypress", Key: "a"}); !isValid {
+		t.Errorf("Validate(Keypress) -> %v; want true", errs)
+	}
+
+	schema := xschema.Create().AddUnion("event", union)
+	_, mapErrs := schema.ValidateMap(map[string]interface{}{"event": map[string]interface{}{"type": "click", "x": 1}})
+
+	if mapErrs["event.y"] == nil {
+		t.Errorf("ValidateMap() -> %v; want error on event.y", mapErrs)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	contact := xschema.AnyOf(
+		xstring.Create().Email(),
+		xstring.Create().Pattern(*regexp.MustCompile(`^\+\d{7,15}$`), "must be a phone number"),
+	)
+
+	for _, value := range []string{"john@example.com", "+380501234567"} {
+		if isValid, errs := contact.Validate(value); !isValid {
+			t.Errorf("AnyOf(%s) -> %v; want true", value, errs)
+		}
+	}
+
+	_, errs := contact.Validate("john")
+
+	if len(errs) != 1 || errs[0].Error() != "must match at least one of: (0) must be a valid email; (1) must be a phone number" {
+		t.Errorf("AnyOf(john) -> %v; want both branch reasons", errs)
+	}
+
+	schema := xschema.Create().AddComposite("Contact", contact)
+
+	if _, errs := schema.ValidateMap(map[string]interface{}{"Contact": 42}); errs["Contact"][0].(*helpers.ValidationError).Rule != "AnyOf" {
+		t.Errorf("ValidateMap(42) -> %v; want AnyOf error on Contact", errs)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	xc := xschema.AllOf(xstring.Create().Required().Min(3), xstring.Create().Lower())
+
+	if isValid, _ := xc.Validate("abc"); !isValid {
+		t.Errorf("AllOf(abc) -> false; want true")
+	}
+
+	if _, errs := xc.Validate("AB"); len(errs) != 2 {
+		t.Errorf("AllOf(AB) -> %v; want Min and Lower errors", errs)
+	}
+
+	schema := xschema.Create().AddComposite("Name", xc)
+
+	if _, errs := schema.ValidateMap(map[string]interface{}{}); errs["Name"] == nil {
+		t.Errorf("ValidateMap({}) -> %v; want Name to be required", errs)
+	}
+}
+
+func TestNot(t *testing.T) {
+	xc := xschema.Not(xstring.Create().OneOf([]string{"admin", "root"}))
+
+	if isValid, _ := xc.Validate("john"); !isValid {
+		t.Errorf("Not(john) -> false; want true")
+	}
+
+	if _, errs := xc.Validate("root"); len(errs) != 1 || errs[0].(*helpers.ValidationError).Rule != "Not" {
+		t.Errorf("Not(root) -> %v; want Not error", errs)
+	}
+
+	if out := xc.String(); out != "Not(XString(OneOf,),)" {
+		t.Errorf("String() -> %s; want Not(XString(OneOf,),)", out)
+	}
+}
+
+func TestOneOfSchemas(t *testing.T) {
+	xc := xschema.OneOfSchemas(xnumber.Create().MultipleOf(3), xnumber.Create().MultipleOf(5))
+
+	if isValid, _ := xc.Validate(9); !isValid {
+		t.Errorf("OneOfSchemas(9) -> false; want true")
+	}
+
+	if _, errs := xc.Validate(15); len(errs) != 1 || errs[0].Error() != "must match exactly one schema, matched [0 1]" {
+		t.Errorf("OneOfSchemas(15) -> %v; want exactly one error", errs)
+	}
+
+	if _, errs := xc.Validate(15); len(errs) != 1 || !reflect.DeepEqual(errs[0].(*helpers.ValidationError).Params["matches"], []int{0, 1}) {
+		t.Errorf("OneOfSchemas(15) -> %v; want matches [0 1]", errs)
+	}
+
+	if _, errs := xc.Validate(7); len(errs) != 1 || errs[0].Error() != "must match exactly one of: (0) must be a multiple of: 3; (1) must be a multiple of: 5" {
+		t.Errorf("OneOfSchemas(7) -> %v; want OneOfSchemas error", errs)
+	}
+
+	if isValid, _ := xc.Nullable().Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+}
+
+func TestLazy(t *testing.T) {
+	var comment xschema.XSchema
+
+	comment = xschema.Create().
+		AddString("Text", xstring.Create().Required()).
+		AddArray("Replies", xarray.Create().Of(xschema.Lazy(func() helpers.XObject { return comment }).MaxDepth(8)))
+
+	thread := map[string]interface{}{
+		"Text": "first",
+		"Replies": []interface{}{
+			map[string]interface{}{"Text": "reply", "Replies": []interface{}{
+				map[string]interface{}{"Text": ""},
+			}},
+		},
+	}
+
+	_, errs := comment.ValidateMap(thread)
+
+	if len(errs) != 1 || errs["Replies[0].Replies[0].Text"] == nil {
+		t.Errorf("ValidateMap(%v) -> %v; want error on Replies[0].Replies[0].Text", thread, errs)
+	}
+
+	cyclic := map[string]interface{}{"Text": "loop"}
+	cyclic["Replies"] = []interface{}{cyclic}
+
+	if isValid, errs := comment.ValidateMap(cyclic); !isValid {
+		t.Errorf("ValidateMap(cyclic) -> %v; want true", errs)
+	}
+
+	cyclic["Text"] = ""
+	_, errs = comment.ValidateMap(cyclic)
+
+	if len(errs) != 1 || errs["Text"] == nil {
+		t.Errorf("ValidateMap(cyclic) -> %v; want a single error on Text", errs)
+	}
+}
+
+func TestLazyDepth(t *testing.T) {
+	type Node struct {
+		Name     string  `x:"Required"`
+		Children []*Node `x:"Items(MaxDepth=40)"`
+	}
+
+	chain := func(length int) *Node {
+		root := &Node{Name: "0"}
+
+		for node, i := root, 1; i < length; i++ {
+			child := &Node{Name: fmt.Sprint(i)}
+			node.Children = []*Node{child}
+			node = child
+		}
+
+		return root
+	}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(chain(40)); !isValid {
+		t.Errorf("ValidateTaggedStruct(chain(40)) -> %v; want true", errs)
+	}
+
+	if _, errs := xschema.ValidateTaggedStruct(chain(42)); !hasRule(errs, "MaxDepth") {
+		t.Errorf("ValidateTaggedStruct(chain(42)) -> %v; want MaxDepth error", errs)
+	}
+
+	type Default struct {
+		Name     string `x:"Required"`
+		Children []*Default
+	}
+
+	deep := &Default{Name: "0"}
+
+	for node, i := deep, 1; i < xschema.DefaultMaxDepth; i++ {
+		child := &Default{Name: fmt.Sprint(i)}
+		node.Children = []*Default{child}
+		node = child
+	}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(deep); !isValid {
+		t.Errorf("ValidateTaggedStruct(deep) -> %v; want true", errs)
+	}
+
+	_, err := xschema.SchemaFor[struct {
+		Child struct{ Name string } `x:"MaxDepth=3"`
+	}]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Rule != "MaxDepth" {
+		t.Errorf("SchemaFor() -> %v; want MaxDepth tag error", err)
+	}
+}
+
+func TestLazyTags(t *testing.T) {
+	type Category struct {
+		Name     string `x:"Required"`
+		Parent   *Category
+		Children []*Category `x:"MaxItems=2"`
+	}
+
+	schema := xschema.MustSchemaFor[Category]()
+
+	if out := schema.String(); out != "XSchema(Name:XString(Required,),Parent:XLazy(Nullable,),Children:XArray(Of:XLazy(Nullable,),MaxItems,),)" {
+		t.Errorf("String() -> %s", out)
+	}
+
+	root := &Category{Name: "Books", Children: []*Category{{Name: "Fiction"}, {Name: ""}}}
+	_, errs := xschema.ValidateTaggedStruct(root)
+
+	if len(errs) != 1 || errs["Children[1].Name"] == nil {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want error on Children[1].Name", root, errs)
+	}
+
+	root.Children[1].Name = "Poetry"
+	root.Children[1].Parent = root
+
+	if isValid, errs := xschema.ValidateTaggedStruct(root); !isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want true", root, errs)
+	}
+
+	leaf := Category{Name: "Poetry", Parent: &Category{Name: "Books"}}
+
+	if isValid, errs := xschema.ValidateTaggedStruct(leaf); !isValid {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want true", leaf, errs)
+	}
+}
+
+func TestLazyCycles(t *testing.T) {
+	type Node struct {
+		Name     string `x:"Required"`
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "a", Parent: root}, {Name: "b", Parent: root}}
+
+	done := make(chan bool)
+
+	go func() {
+		isValid, errs := xschema.ValidateTaggedStruct(root)
+
+		if !isValid {
+			t.Errorf("ValidateTaggedStruct(root) -> %v; want true", errs)
+		}
+
+		root.Children[1].Name = ""
+		_, errs = xschema.ValidateTaggedStruct(root)
+
+		if len(errs) != 1 || errs["Children[1].Name"] == nil {
+			t.Errorf("ValidateTaggedStruct(root) -> %v; want a single error on Children[1].Name", errs)
+		}
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ValidateTaggedStruct(root) did not finish on a tree with back-pointers")
+	}
+}
+
+func hasRule(errs map[string][]error, rule string) bool {
+	for _, pathErrs := range errs {
+		for _, err := range pathErrs {
+			if ve, ok := err.(*helpers.ValidationError); ok && ve.Rule == rule {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestPickOmit(t *testing.T) {
+	create := xschema.Create().
+		AddString("Name", xstring.Create().Required()).
+		AddString("Email", xstring.Create().Required().Email()).
+		AddString("Password", xstring.Create().Required().Min(8))
+
+	if out := create.Pick("Password", "Name", "Unknown").String(); out != "XSchema(Name:XString(Required,),Password:XString(Required,Min,),)" {
+		t.Errorf("Pick() -> %s", out)
+	}
+
+	response := create.Omit("Password")
+
+	if out := response.String(); out != "XSchema(Name:XString(Required,),Email:XString(Required,Email,),)" {
+		t.Errorf("Omit() -> %s", out)
+	}
+
+	if out := create.String(); out != "XSchema(Name:XString(Required,),Email:XString(Required,Email,),Password:XString(Required,Min,),)" {
+		t.Errorf("Omit() mutated the original schema: %s", out)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	create := xschema.Create().
+		AddString("Name", xstring.Create().Required()).
+		AddString("Email", xstring.Create().Required().Email())
+
+	update := create.Partial()
+	values := map[string]interface{}{"Email": "john"}
+
+	if _, errs := update.ValidateMap(values); len(errs) != 1 || errs["Email"] == nil {
+		t.Errorf("ValidateMap(%v) -> %v; want only the Email error", values, errs)
+	}
+
+	if _, errs := create.ValidateMap(values); errs["Name"] == nil {
+		t.Errorf("Partial() mutated the original schema")
+	}
+
+	if _, errs := create.Partial("Email").ValidateMap(map[string]interface{}{}); len(errs) != 1 || errs["Name"] == nil {
+		t.Errorf("Partial(Email) -> %v; want only Name to be required", errs)
+	}
+}
+
+func TestRequiredAll(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Name", xstring.Create()).
+		AddNumber("Age", xnumber.Create().Optional()).
+		RequiredAll()
+
+	if _, errs := schema.ValidateMap(map[string]interface{}{}); len(errs) != 2 {
+		t.Errorf("ValidateMap({}) -> %v; want Name and Age to be required", errs)
+	}
+
+	if isValid, errs := schema.Partial().ValidateMap(map[string]interface{}{}); !isValid {
+		t.Errorf("Partial().ValidateMap({}) -> %v; want true", errs)
+	}
+
+	schema = schema.AddNumber("Age", xnumber.Create().Optional())
+
+	if _, errs := schema.ValidateMap(map[string]interface{}{}); len(errs) != 1 || errs["Name"] == nil {
+		t.Errorf("ValidateMap({}) -> %v; want Age override to be reset", errs)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	base := xschema.Create().AddString("Name", xstring.Create().Required()).Partial()
+	response := base.Extend(xschema.Create().AddNumber("ID", xnumber.Create().Required()))
+
+	if out := response.String(); out != "XSchema(Name:XString(Required,),ID:XNumber(Required,),)" {
+		t.Errorf("Extend() -> %s", out)
+	}
+
+	if _, errs := response.ValidateMap(map[string]interface{}{}); len(errs) != 1 || errs["ID"] == nil {
+		t.Errorf("ValidateMap({}) -> %v; want only ID to be required", errs)
+	}
+}
+
+func TestRename(t *testing.T) {
+	schema := xschema.Create().
+		AddString("name", xstring.Create().Required()).
+		AddString("email", xstring.Create().Email())
+
+	renamed := schema.Rename("name", "fullName")
+
+	if out := renamed.String(); out != "XSchema(fullName:XString(Required,),email:XString(Email,),)" {
+		t.Errorf("Rename() -> %s", out)
+	}
+
+	if _, errs := renamed.ValidateMap(map[string]interface{}{"name": "John"}); errs["fullName"] == nil {
+		t.Errorf("ValidateMap() -> %v; want fullName to be required", errs)
+	}
+
+	if out := schema.Rename("missing", "other").String(); out != schema.String() {
+		t.Errorf("Rename(missing) -> %s; want %s", out, schema.String())
+	}
+
+	if out := schema.Rename("name", "email").String(); out != "XSchema(email:XString(Required,),)" {
+		t.Errorf("Rename(name, email) -> %s", out)
+	}
+}
+
+func TestTagItems(t *testing.T) {
+	type Post struct {
+		Tags   []string   `x:"MaxItems=3,Items(Required, Min=2)"`
+		Scores []*int     `x:"Items='Nullable,Gte=0,Lte=10'"`
+		Matrix [][]string `x:"Items(Items(Length=1))"`
+	}
+
+	schema := xschema.MustSchemaFor[Post]()
+
+	if out := schema.String(); out != "XSchema(Tags:XArray(Of:XString(Required,Min,),MaxItems,),Scores:XArray(Of:XNumber(Nullable,Gte,Lte,),),Matrix:XArray(Of:XArray(Of:XString(Length,),),),)" {
+		t.Errorf("String() -> %s", out)
+	}
+
+	score := 11
+	post := Post{Tags: []string{"go", ""}, Scores: []*int{nil, &score}, Matrix: [][]string{{"a", "bc"}}}
+	_, errs := schema.ValidateStruct(post)
+
+	if len(errs) != 3 || errs["Tags[1]"] == nil || errs["Scores[1]"] == nil || errs["Matrix[0][1]"] == nil {
+		t.Errorf("ValidateStruct(%v) -> %v; want errors on Tags[1], Scores[1] and Matrix[0][1]", post, errs)
+	}
+
+	for _, tag := range []string{"Items(Requried)", "Items('Min=')"} {
+		_, err := xschema.CompileTagged(reflect.StructOf([]reflect.StructField{
+			{Name: "Tags", Type: reflect.TypeOf([]string{}), Tag: reflect.StructTag(`x:"` + tag + `"`)},
+		}))
+
+		if te, ok := err.(*helpers.TagError); !ok || te.Field != "Tags" {
+			t.Errorf("CompileTagged(%s) -> %v; want tag error on Tags", tag, err)
+		}
+	}
+}
+
+func TestRequiredObjects(t *testing.T) {
+	address := xschema.Create().AddString("City", xstring.Create().Required())
+	schema := xschema.Create().
+		AddObject("Address", address).
+		AddObject("Meta", xschema.Create().AddString("Note", xstring.Create())).
+		AddArray("Tags", xarray.Create().Required()).
+		AddUnion("Event", xschema.DiscriminatedUnion("type").Required())
+
+	_, errs := schema.ValidateMap(map[string]interface{}{})
+
+	if len(errs) != 3 || errs["Address"] == nil || errs["Tags"] == nil || errs["Event"] == nil {
+		t.Errorf("ValidateMap({}) -> %v; want Address, Tags and Event to be required", errs)
+	}
+
+	schema = schema.AddObject("Address", address.Optional()).AddObject("Meta", xschema.Create().Required())
+	_, errs = schema.ValidateMap(map[string]interface{}{"Tags": []interface{}{}, "Event": map[string]interface{}{"type": "x"}})
+
+	if len(errs) != 2 || errs["Meta"] == nil || errs["Event.type"] == nil {
+		t.Errorf("ValidateMap() -> %v; want Meta and Event.type errors", errs)
+	}
+
+	if out := xschema.Create().Required().String(); out != "XSchema(Required,)" {
+		t.Errorf("String() -> %s; want XSchema(Required,)", out)
+	}
+
+	type Profile struct {
+		Address struct {
+			City string
+		} `x:"Required"`
+	}
+
+	if out := xschema.MustSchemaFor[Profile]().String(); out != "XSchema(Address:XSchema(Required,),)" {
+		t.Errorf("MustSchemaFor[Profile]() -> %s", out)
+	}
+}
+
+func TestStripValidate(t *testing.T) {
+	schema := xschema.Create().AddString("Name", xstring.Create()).UnknownKeys(xschema.Strip)
+	values := map[string]interface{}{"Name": "John", "Extra": 1}
+
+	if isValid, errs := schema.ValidateMap(values); !isValid {
+		t.Errorf("ValidateMap(%v) -> %v; want unknown keys to be accepted", values, errs)
+	}
+
+	if parsed, _, _ := schema.Parse(values); len(parsed) != 1 {
+		t.Errorf("Parse(%v) -> %v; want Extra to be removed", values, parsed)
+	}
+}
+
+func TestTagOpaqueStruct(t *testing.T) {
+	type Event struct {
+		Name      string `x:"Required"`
+		CreatedAt time.Time
+		UpdatedAt *time.Time
+	}
+
+	if out := xschema.MustSchemaFor[Event]().String(); out != "XSchema(Name:XString(Required,),)" {
+		t.Errorf("MustSchemaFor[Event]() -> %s", out)
+	}
+
+	_, err := xschema.SchemaFor[struct {
+		CreatedAt time.Time `x:"Required"`
+	}]()
+
+	if te, ok := err.(*helpers.TagError); !ok || te.Field != "CreatedAt" || te.Err.Error() != "unsupported type: time.Time" {
+		t.Errorf("SchemaFor() -> %v; want unsupported type time.Time on CreatedAt", err)
+	}
+}
+
+func TestTagNestedSlices(t *testing.T) {
+	type Item struct {
+		SKU string `x:"Required"`
+	}
+
+	type Order struct {
+		Items    []Item
+		Extra    []*Item
+		Fixed    [2]Item
+		Notes    []struct{ Text string }
+		Children []time.Time
+	}
+
+	if out := xschema.MustSchemaFor[Order]().String(); out != "XSchema(Items:XArray(Of:XSchema(SKU:XString(Required,),),),Extra:XArray(Of:XSchema(Nullable,SKU:XString(Required,),),),Fixed:XArray(Of:XSchema(SKU:XString(Required,),),),)" {
+		t.Errorf("MustSchemaFor[Order]() -> %s", out)
+	}
+
+	order := Order{Items: []Item{{SKU: ""}}, Extra: []*Item{nil, {SKU: ""}}, Fixed: [2]Item{{"a"}, {"b"}}}
+	_, errs := xschema.ValidateTaggedStruct(order)
+
+	if len(errs) != 2 || errs["Items[0].SKU"] == nil || errs["Extra[1].SKU"] == nil {
+		t.Errorf("ValidateTaggedStruct(%v) -> %v; want errors on Items[0].SKU and Extra[1].SKU", order, errs)
+	}
+}
+
+func TestCompositeRedact(t *testing.T) {
+	password := xschema.AnyOf(xstring.Create().Min(12), xstring.Create().Pattern(*regexp.MustCompile(`\d`)))
+	_, errs := password.Validate("secret")
+	ve := errs[0].(*helpers.ValidationError).Redact()
+
+	if ve.Value != nil {
+		t.Errorf("Redact() -> %v; want nil value", ve.Value)
+	}
+
+	for _, branch := range ve.Params["errors"].([]helpers.ValidationErrors) {
+		for _, err := range branch {
+			if err.Value != nil || err.Rule == "" {
+				t.Errorf("Params[errors] -> %+v; want redacted errors with rules", err)
+			}
+		}
+	}
+}
+
+func TestCompositeNil(t *testing.T) {
+	cases := map[string]func(){
+		"Not(nil)":            func() { xschema.Not(nil) },
+		"AnyOf(XString, nil)": func() { xschema.AnyOf(xstring.Create(), nil) },
+		"AllOf(nil)":          func() { xschema.AllOf(nil) },
+		"OneOfSchemas(nil)":   func() { xschema.OneOfSchemas(nil, xstring.Create()) },
+	}
+
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+
+			f()
+		}()
+	}
+}
+
+func TestDerivedRefinements(t *testing.T) {
+	create := xschema.Create().
+		AddNumber("ID", xnumber.Create()).
+		AddString("Password", xstring.Create().Required()).
+		AddString("PasswordConfirm", xstring.Create().Required()).
+		RefineKey("PasswordConfirm", "EqualTo", func(values map[string]interface{}) bool {
+			return values["Password"] == values["PasswordConfirm"]
+		}, "must match Password")
+
+	values := map[string]interface{}{"Password": "a", "PasswordConfirm": "b"}
+
+	for name, schema := range map[string]xschema.XSchema{
+		"Create":  create,
+		"Omit":    create.Omit("ID"),
+		"Pick":    create.Pick("Password", "PasswordConfirm"),
+		"Rename":  create.Rename("ID", "Id"),
+		"Partial": create.Partial(),
+	} {
+		if _, errs := schema.ValidateMap(values); errs["PasswordConfirm"] == nil {
+			t.Errorf("%s.ValidateMap(%v) -> %v; want EqualTo error on PasswordConfirm", name, values, errs)
+		}
+	}
+
+	if isValid, errs := create.Omit("PasswordConfirm").ValidateMap(map[string]interface{}{"Password": "a"}); !isValid {
+		t.Errorf("Omit(PasswordConfirm) -> %v; want the bound refinement to be dropped", errs)
+	}
+}
+
+func TestRenameRefinements(t *testing.T) {
+	schema := xschema.Create().
+		AddString("Password", xstring.Create()).
+		AddString("PasswordConfirm", xstring.Create()).
+		RefineKey("PasswordConfirm", "EqualTo", func(values map[string]interface{}) bool {
+			return values["Password"] == values["PasswordConfirm"]
+		}, "must match Password").
+		Custom("NotEmpty", func(values map[string]interface{}) error {
+			if values["Password"] == "" {
+				return helpers.WithPath("Password", nil, fmt.Errorf("must not be empty"))
+			}
+			return nil
+		}).
+		Rename("PasswordConfirm", "confirm").
+		Rename("Password", "password")
+
+	if isValid, errs := schema.ValidateMap(map[string]interface{}{"password": "a", "confirm": "a"}); !isValid {
+		t.Errorf("ValidateMap() -> %v; want true", errs)
+	}
+
+	_, errs := schema.ValidateMap(map[string]interface{}{"password": "a", "confirm": "b"})
+
+	if ve, ok := errs["confirm"][0].(*helpers.ValidationError); len(errs) != 1 || !ok || ve.Rule != "EqualTo" || ve.Value != "b" {
+		t.Errorf("ValidateMap() -> %v; want EqualTo error on confirm", errs)
+	}
+
+	_, errs = schema.ValidateMap(map[string]interface{}{"password": "", "confirm": ""})
+
+	if len(errs) != 1 || errs["password"] == nil {
+		t.Errorf("ValidateMap() -> %v; want NotEmpty error on password", errs)
+	}
 }
@@ -2,104 +2,203 @@ package xstring
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/radchukd/go-xschema/src/helpers"
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
+var (
+	alphanumPattern = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	lowerPattern    = regexp.MustCompile(`^[a-z]+$`)
+	upperPattern    = regexp.MustCompile(`^[A-Z]+$`)
+	emailPattern    = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
+	urlPattern      = regexp.MustCompile(`^(?:[a-zA-Z0-9]{1,62}(?:[-\.][a-zA-Z0-9]{1,62})+)(:\d+)?$`)
+	uuidPattern     = regexp.MustCompile(`^(?:[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}|00000000-0000-0000-0000-000000000000)$`)
 )
 
 type XString struct {
-	validations map[string]helpers.XValidation[string]
+	optional    bool
+	nullable    bool
+	validations []helpers.XValidation[string]
 }
 
 func Create() XString {
 	xs := XString{}
-	xs.validations = make(map[string]helpers.XValidation[string])
+	xs.validations = make([]helpers.XValidation[string], 0)
 	return xs
 }
 
-func FromTags(validationTags []string) XString {
+var registry = helpers.NewRuleRegistry[string]("Optional", "Nullable", "Required", "When", "Items", "MaxDepth", "Alphanum", "StartsWith", "EndsWith", "Lower", "Upper", "Length", "Min", "Max", "Pattern", "Email", "URL", "UUID", "OneOf")
+
+func RegisterRule(name string, factory helpers.RuleFactory[string]) error {
+	return registry.Register(name, factory)
+}
+
+func FromTags(validationTags []string) (XString, error) {
+	rules, err := helpers.ParseTags(validationTags)
+
+	if err != nil {
+		return Create(), err
+	}
+
+	return FromRules(rules)
+}
+
+func FromRules(rules []xtag.Rule) (XString, error) {
 	xs := Create()
 
-	for _, v := range validationTags {
-		nameArg := strings.Split(v, "=")
+	for _, r := range rules {
+		rule, args := r.Name, r.Args
+
+		var err error
 
-		switch nameArg[0] {
+		switch rule {
+		case "Optional":
+			xs, err = xs.Optional(), helpers.NoTagArgs(args)
+		case "Nullable":
+			xs, err = xs.Nullable(), helpers.NoTagArgs(args)
 		case "Required":
-			xs = xs.Required()
+			xs, err = xs.Required(), helpers.NoTagArgs(args)
 		case "Alphanum":
-			xs = xs.Alphanum()
+			xs, err = xs.Alphanum(), helpers.NoTagArgs(args)
 		case "StartsWith":
-			xs = xs.StartsWith(nameArg[1])
+			var arg string
+			arg, err = helpers.StringTagArg(args)
+			xs = xs.StartsWith(arg)
 		case "EndsWith":
-			xs = xs.EndsWith(nameArg[1])
+			var arg string
+			arg, err = helpers.StringTagArg(args)
+			xs = xs.EndsWith(arg)
 		case "Lower":
-			xs = xs.Lower()
+			xs, err = xs.Lower(), helpers.NoTagArgs(args)
 		case "Upper":
-			xs = xs.Upper()
+			xs, err = xs.Upper(), helpers.NoTagArgs(args)
 		case "Length":
-			ln, _ := strconv.Atoi(nameArg[1])
-			xs = xs.Length(ln)
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xs = xs.Length(n)
 		case "Min":
-			ln, _ := strconv.Atoi(nameArg[1])
-			xs = xs.Min(ln)
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xs = xs.Min(n)
 		case "Max":
-			ln, _ := strconv.Atoi(nameArg[1])
-			xs = xs.Max(ln)
+			var n int
+			n, err = helpers.IntTagArg(args)
+			xs = xs.Max(n)
 		case "Pattern":
-			pt := regexp.MustCompile(nameArg[1])
-			xs = xs.Pattern(*pt)
+			var arg string
+			var pt *regexp.Regexp
+
+			if arg, err = helpers.StringTagArg(args); err == nil {
+				if pt, err = regexp.Compile(arg); err == nil {
+					xs = xs.Pattern(*pt)
+				}
+			}
 		case "Email":
-			xs = xs.Email()
+			xs, err = xs.Email(), helpers.NoTagArgs(args)
 		case "URL":
-			xs = xs.URL()
+			xs, err = xs.URL(), helpers.NoTagArgs(args)
 		case "UUID":
-			xs = xs.UUID()
+			xs, err = xs.UUID(), helpers.NoTagArgs(args)
 		case "OneOf":
-			var ooArr []string
-			json.Unmarshal([]byte(nameArg[1]), &ooArr)
-			xs = xs.OneOf(ooArr)
+			var values []string
+			values, err = helpers.ListTagArg(args, func(arg string) (string, error) { return arg, nil })
+			xs = xs.OneOf(values)
+		default:
+			var validation helpers.XValidation[string]
+
+			if validation, err = registry.Build(rule, args); err == nil {
+				xs = xs.withValidation(validation)
+			}
+		}
+
+		if err != nil {
+			return xs, &helpers.TagError{Rule: rule, Arg: strings.Join(args, ","), Err: err}
 		}
 	}
 
+	return xs, nil
+}
+
+func MustFromTags(validationTags []string) XString {
+	xs, err := FromTags(validationTags)
+
+	if err != nil {
+		panic(err)
+	}
+
 	return xs
 }
 
-func (xs XString) addValidation(ruleName string, err error, validation func(string) bool) XString {
-	xs.validations[ruleName] = helpers.XValidation[string]{E: err, F: validation}
+func (xs XString) addValidation(ruleName string, params map[string]interface{}, message string, validation func(string) bool) XString {
+	return xs.withValidation(helpers.XValidation[string]{E: helpers.NewError(ruleName, params, message), F: validation})
+}
+
+func (xs XString) withValidation(validation helpers.XValidation[string]) XString {
+	validations := make([]helpers.XValidation[string], len(xs.validations), len(xs.validations)+1)
+	copy(validations, xs.validations)
+
+	xs.validations = append(validations, validation)
 	return xs
 }
 
 func (xs XString) Validate(val interface{}) (bool, []error) {
 	validationErrors := make([]error, 0)
 
-	if reflect.TypeOf(val).Kind() != reflect.String {
-		validationErrors = append(validationErrors, fmt.Errorf("invalid type"))
+	val = helpers.Indirect(val)
+
+	if val == nil {
+		if xs.nullable {
+			return true, validationErrors
+		}
+
+		validationErrors = append(validationErrors, helpers.NullError())
+		return false, validationErrors
+	}
+
+	if _, ok := val.(json.Number); ok || reflect.TypeOf(val).Kind() != reflect.String {
+		validationErrors = append(validationErrors, helpers.TypeError("string", val))
 		return false, validationErrors
 	}
 
-	value := val.(string)
+	value := reflect.ValueOf(val).String()
 
 	for _, validation := range xs.validations {
-		isValid := validation.F(value)
-
-		if !isValid {
-			validationErrors = append(validationErrors, validation.E)
+		if err := validation.Check(value, val); err != nil {
+			validationErrors = append(validationErrors, err)
 		}
 	}
 
 	return len(validationErrors) == 0, validationErrors
 }
 
+func (xs XString) IsRequired() bool {
+	for _, validation := range xs.validations {
+		if validation.E.Rule == "Required" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (xs XString) String() string {
 	out := "XString("
 
-	for validationName := range xs.validations {
-		out += validationName + ","
+	if xs.optional {
+		out += "Optional,"
+	}
+
+	if xs.nullable {
+		out += "Nullable,"
+	}
+
+	for _, validation := range xs.validations {
+		out += validation.E.Rule + ","
 	}
 
 	out += ")"
@@ -107,10 +206,29 @@ func (xs XString) String() string {
 	return out
 }
 
+func (xs XString) Optional() XString {
+	xs.optional = true
+	return xs
+}
+
+func (xs XString) Nullable() XString {
+	xs.nullable = true
+	return xs
+}
+
+func (xs XString) IsOptional() bool {
+	return xs.optional
+}
+
+func (xs XString) IsNullable() bool {
+	return xs.nullable
+}
+
 func (xs XString) Required(errorMessage ...string) XString {
 	return xs.addValidation(
 		"Required",
-		errors.New(append(errorMessage, "must be non-empty")[0]),
+		nil,
+		append(errorMessage, "must be non-empty")[0],
 		func(value string) bool {
 			return value != ""
 		})
@@ -119,16 +237,18 @@ func (xs XString) Required(errorMessage ...string) XString {
 func (xs XString) Alphanum(errorMessage ...string) XString {
 	return xs.addValidation(
 		"Alphanum",
-		errors.New(append(errorMessage, "must be alphanumeric")[0]),
+		nil,
+		append(errorMessage, "must be alphanumeric")[0],
 		func(value string) bool {
-			return regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString(value)
+			return alphanumPattern.MatchString(value)
 		})
 }
 
 func (xs XString) StartsWith(prefix string, errorMessage ...string) XString {
 	return xs.addValidation(
 		"StartsWith",
-		errors.New(append(errorMessage, fmt.Sprintf("must start with: %v", prefix))[0]),
+		map[string]interface{}{"prefix": prefix},
+		append(errorMessage, fmt.Sprintf("must start with: %v", prefix))[0],
 		func(value string) bool {
 			return strings.HasPrefix(value, prefix)
 		})
@@ -137,7 +257,8 @@ func (xs XString) StartsWith(prefix string, errorMessage ...string) XString {
 func (xs XString) EndsWith(suffix string, errorMessage ...string) XString {
 	return xs.addValidation(
 		"EndsWith",
-		errors.New(append(errorMessage, fmt.Sprintf("must start with: %v", suffix))[0]),
+		map[string]interface{}{"suffix": suffix},
+		append(errorMessage, fmt.Sprintf("must end with: %v", suffix))[0],
 		func(value string) bool {
 			return strings.HasSuffix(value, suffix)
 		})
@@ -146,25 +267,28 @@ func (xs XString) EndsWith(suffix string, errorMessage ...string) XString {
 func (xs XString) Lower(errorMessage ...string) XString {
 	return xs.addValidation(
 		"Lower",
-		errors.New(append(errorMessage, "must be lowercase")[0]),
+		nil,
+		append(errorMessage, "must be lowercase")[0],
 		func(value string) bool {
-			return regexp.MustCompile(`^[a-z]+$`).MatchString(value)
+			return lowerPattern.MatchString(value)
 		})
 }
 
 func (xs XString) Upper(errorMessage ...string) XString {
 	return xs.addValidation(
 		"Upper",
-		errors.New(append(errorMessage, "must be uppercase")[0]),
+		nil,
+		append(errorMessage, "must be uppercase")[0],
 		func(value string) bool {
-			return regexp.MustCompile(`^[A-Z]+$`).MatchString(value)
+			return upperPattern.MatchString(value)
 		})
 }
 
 func (xs XString) Length(length int, errorMessage ...string) XString {
 	return xs.addValidation(
 		"Length",
-		errors.New(append(errorMessage, fmt.Sprintf("must be of length equal to: %v", length))[0]),
+		map[string]interface{}{"length": length},
+		append(errorMessage, fmt.Sprintf("must be of length equal to: %v", length))[0],
 		func(value string) bool {
 			return len(value) == length
 		})
@@ -173,7 +297,8 @@ func (xs XString) Length(length int, errorMessage ...string) XString {
 func (xs XString) Min(minLength int, errorMessage ...string) XString {
 	return xs.addValidation(
 		"Min",
-		errors.New(append(errorMessage, fmt.Sprintf("must be of length greater than: %v", minLength))[0]),
+		map[string]interface{}{"min": minLength},
+		append(errorMessage, fmt.Sprintf("must be of length greater than: %v", minLength))[0],
 		func(value string) bool {
 			return len(value) >= minLength
 		})
@@ -182,40 +307,44 @@ func (xs XString) Min(minLength int, errorMessage ...string) XString {
 func (xs XString) Max(maxLength int, errorMessage ...string) XString {
 	return xs.addValidation(
 		"Max",
-		errors.New(append(errorMessage, fmt.Sprintf("must be of length smaller than: %v", maxLength))[0]),
+		map[string]interface{}{"max": maxLength},
+		append(errorMessage, fmt.Sprintf("must be of length smaller than: %v", maxLength))[0],
 		func(value string) bool {
 			return len(value) <= maxLength
 		})
 }
 
 func (xs XString) Pattern(pattern regexp.Regexp, errorMessage ...string) XString {
-	return xs.addValidation(
-		"Pattern",
-		errors.New(append(errorMessage, fmt.Sprintf("must match pattern: %s", pattern.String()))[0]),
-		func(value string) bool {
-			return pattern.MatchString(value)
-		})
+	return xs.matches("Pattern", &pattern, append(errorMessage, fmt.Sprintf("must match pattern: %s", pattern.String()))[0])
 }
 
 func (xs XString) Email(errorMessage ...string) XString {
-	pattern := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
-	return xs.Pattern(*pattern, errorMessage...)
+	return xs.matches("Email", emailPattern, append(errorMessage, "must be a valid email")[0])
 }
 
 func (xs XString) URL(errorMessage ...string) XString {
-	pattern := regexp.MustCompile(`^(?:[a-zA-Z0-9]{1,62}(?:[-\.][a-zA-Z0-9]{1,62})+)(:\d+)?$`)
-	return xs.Pattern(*pattern, errorMessage...)
+	return xs.matches("URL", urlPattern, append(errorMessage, "must be a valid URL")[0])
 }
 
 func (xs XString) UUID(errorMessage ...string) XString {
-	pattern := regexp.MustCompile(`^(?:[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}|00000000-0000-0000-0000-000000000000)$`)
-	return xs.Pattern(*pattern, errorMessage...)
+	return xs.matches("UUID", uuidPattern, append(errorMessage, "must be a valid UUID")[0])
+}
+
+func (xs XString) matches(ruleName string, pattern *regexp.Regexp, message string) XString {
+	return xs.addValidation(
+		ruleName,
+		map[string]interface{}{"pattern": pattern.String()},
+		message,
+		func(value string) bool {
+			return pattern.MatchString(value)
+		})
 }
 
 func (xs XString) OneOf(possibleValues []string, errorMessage ...string) XString {
 	return xs.addValidation(
 		"OneOf",
-		errors.New(append(errorMessage, fmt.Sprintf("must be one of: %v", possibleValues))[0]),
+		map[string]interface{}{"values": possibleValues},
+		append(errorMessage, fmt.Sprintf("must be one of: %v", possibleValues))[0],
 		func(value string) bool {
 			for _, v := range possibleValues {
 				if value == v {
@@ -225,3 +354,11 @@ func (xs XString) OneOf(possibleValues []string, errorMessage ...string) XString
 			return false
 		})
 }
+
+func (xs XString) Custom(ruleName string, check func(string) error) XString {
+	return xs.withValidation(registry.MustInline(ruleName, helpers.XValidation[string]{E: helpers.NewError(ruleName, nil, "is invalid"), C: check}))
+}
+
+func (xs XString) Refine(ruleName string, check func(string) bool, errorMessage ...string) XString {
+	return xs.withValidation(registry.MustInline(ruleName, helpers.XValidation[string]{E: helpers.NewError(ruleName, nil, append(errorMessage, "is invalid")[0]), F: check}))
+}
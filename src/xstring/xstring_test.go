@@ -1,10 +1,13 @@
 package xstring_test
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/radchukd/go-xschema/src/helpers"
 	"github.com/radchukd/go-xschema/src/xstring"
 )
 
@@ -291,3 +294,277 @@ func TestOneOf(t *testing.T) {
 		t.Errorf("OneOf(%v,%v) -> true; want false", possibleValues, value)
 	}
 }
+
+func TestOrder(t *testing.T) {
+	xs := xstring.Create().Required().Min(5).Alphanum().Max(10)
+
+	if out := xs.String(); out != "XString(Required,Min,Alphanum,Max,)" {
+		t.Errorf("String() -> %s; want XString(Required,Min,Alphanum,Max,)", out)
+	}
+
+	value := "a_b"
+
+	for i := 0; i < 10; i++ {
+		_, errs := xs.Validate(value)
+
+		if len(errs) != 2 || errs[0].(*helpers.ValidationError).Rule != "Min" || errs[1].(*helpers.ValidationError).Rule != "Alphanum" {
+			t.Fatalf("Validate(%s) -> %v; want [Min Alphanum]", value, errs)
+		}
+	}
+}
+
+func TestMultipleRules(t *testing.T) {
+	var value string
+	xs := xstring.Create().
+		Email().
+		Pattern(*regexp.MustCompile(`@example\.com$`)).
+		Pattern(*regexp.MustCompile(`^[a-z]`))
+
+	if out := xs.String(); out != "XString(Email,Pattern,Pattern,)" {
+		t.Errorf("String() -> %s; want XString(Email,Pattern,Pattern,)", out)
+	}
+
+	value = "john@gmail.com"
+
+	if _, errs := xs.Validate(value); len(errs) != 1 || errs[0].(*helpers.ValidationError).Rule != "Pattern" {
+		t.Errorf("Validate(%s) -> %v; want [Pattern]", value, errs)
+	}
+
+	value = "1john"
+
+	if _, errs := xs.Validate(value); len(errs) != 3 || errs[0].(*helpers.ValidationError).Rule != "Email" {
+		t.Errorf("Validate(%s) -> %v; want [Email Pattern Pattern]", value, errs)
+	}
+
+	value = "john@example.com"
+
+	if isValid, _ := xs.Validate(value); !isValid {
+		t.Errorf("Validate(%s) -> false; want true", value)
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	value := "abcdefg"
+	base := xstring.Create().Min(2)
+	a := base.Max(5)
+	b := base.Max(10)
+
+	if isValid, _ := a.Validate(value); isValid {
+		t.Errorf("Max(5,%s) -> true; want false", value)
+	}
+
+	if isValid, _ := b.Validate(value); !isValid {
+		t.Errorf("Max(10,%s) -> false; want true", value)
+	}
+
+	if out := base.String(); out != "XString(Min,)" {
+		t.Errorf("String() -> %s; want XString(Min,)", out)
+	}
+}
+
+func TestNullable(t *testing.T) {
+	xs := xstring.Create()
+
+	if isValid, _ := xs.Validate(nil); isValid {
+		t.Errorf("Validate(nil) -> true; want false")
+	}
+
+	xs = xs.Nullable()
+
+	if isValid, _ := xs.Validate(nil); !isValid {
+		t.Errorf("Nullable(nil) -> false; want true")
+	}
+
+	if isValid, _ := xs.Validate("abc"); !isValid {
+		t.Errorf("Nullable(%v) -> false; want true", "abc")
+	}
+}
+
+func TestOptional(t *testing.T) {
+	xs := xstring.Create().Required()
+
+	if xs.IsOptional() {
+		t.Errorf("IsOptional() -> true; want false")
+	}
+
+	xs = xs.Optional()
+
+	if !xs.IsOptional() || xs.String() != "XString(Optional,Required,)" {
+		t.Errorf("Optional() -> %s; want XString(Optional,Required,)", xs.String())
+	}
+}
+
+func TestFromTags(t *testing.T) {
+	xs, err := xstring.FromTags([]string{"Required", "Min=2", "Pattern=^[a-z]+$"})
+
+	if err != nil || xs.String() != "XString(Required,Min,Pattern,)" {
+		t.Errorf("FromTags() -> %s, %v; want XString(Required,Min,Pattern,)", xs.String(), err)
+	}
+
+	invalidTags := map[string]string{
+		"Min=abc":     "Min",
+		"Requried":    "Requried",
+		"Pattern='['": "Pattern",
+		"Required=1":  "Required",
+		"Max":         "Max",
+	}
+
+	for tag, rule := range invalidTags {
+		_, err := xstring.FromTags([]string{tag})
+
+		if te, ok := err.(*helpers.TagError); !ok || te.Rule != rule {
+			t.Errorf("FromTags(%s) -> %v; want tag error for %s", tag, err, rule)
+		}
+	}
+}
+
+func TestFromTagsGrammar(t *testing.T) {
+	xs, err := xstring.FromTags([]string{"Pattern=^[a-z]{1,3}$", `OneOf=["abc","a,b"]`})
+
+	if err != nil || xs.String() != "XString(Pattern,OneOf,)" {
+		t.Fatalf("FromTags() -> %s, %v; want XString(Pattern,OneOf,)", xs.String(), err)
+	}
+
+	if isValid, _ := xs.Validate("abc"); !isValid {
+		t.Errorf("FromTags(%s) -> false; want true", "abc")
+	}
+
+	if isValid, _ := xs.Validate("abcd"); isValid {
+		t.Errorf("FromTags(%s) -> true; want false", "abcd")
+	}
+
+	xs, err = xstring.FromTags([]string{`StartsWith='a=b'`})
+
+	if isValid, _ := xs.Validate("a=bc"); err != nil || !isValid {
+		t.Errorf("FromTags(StartsWith='a=b') -> %v, %v; want true", isValid, err)
+	}
+
+	xs, err = xstring.FromTags([]string{"OneOf(red, green, 'blue, dark')"})
+
+	if err != nil {
+		t.Fatalf("FromTags(OneOf) -> %v; want nil", err)
+	}
+
+	for _, value := range []string{"red", "green", "blue, dark"} {
+		if isValid, _ := xs.Validate(value); !isValid {
+			t.Errorf("OneOf(%s) -> false; want true", value)
+		}
+	}
+
+	if _, err := xstring.FromTags([]string{"Pattern=[a-z"}); err == nil {
+		t.Errorf("FromTags(Pattern=[a-z) -> nil; want syntax error")
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	sku := regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)
+	err := xstring.RegisterRule("SKU", func(args []string) (helpers.XValidation[string], error) {
+		return helpers.XValidation[string]{
+			E: helpers.NewError("SKU", nil, "must be a SKU"),
+			F: sku.MatchString,
+		}, helpers.NoTagArgs(args)
+	})
+
+	if err != nil {
+		t.Fatalf("RegisterRule(SKU) -> %v; want nil", err)
+	}
+
+	xs, err := xstring.FromTags([]string{"Required", "SKU"})
+
+	if err != nil || xs.String() != "XString(Required,SKU,)" {
+		t.Fatalf("FromTags(SKU) -> %s, %v; want XString(Required,SKU,)", xs.String(), err)
+	}
+
+	if isValid, _ := xs.Validate("ABC-1234"); !isValid {
+		t.Errorf("SKU(%s) -> false; want true", "ABC-1234")
+	}
+
+	if _, errs := xs.Validate("abc"); len(errs) != 1 || errs[0].Error() != "must be a SKU" {
+		t.Errorf("SKU(%s) -> %v; want [must be a SKU]", "abc", errs)
+	}
+
+	if _, err := xstring.FromTags([]string{"SKU=1"}); err == nil {
+		t.Errorf("FromTags(SKU=1) -> nil; want tag error")
+	}
+
+	for _, name := range []string{"SKU", "Email", "When", "Items", "MaxDepth"} {
+		if err := xstring.RegisterRule(name, func(args []string) (helpers.XValidation[string], error) {
+			return helpers.XValidation[string]{}, nil
+		}); !errors.Is(err, helpers.ErrRuleExists) {
+			t.Errorf("RegisterRule(%s) -> %v; want ErrRuleExists", name, err)
+		}
+	}
+}
+
+func TestMustFromTags(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustFromTags(Min=abc) did not panic")
+		}
+	}()
+
+	xstring.MustFromTags([]string{"Min=abc"})
+}
+
+func TestCustom(t *testing.T) {
+	reserved := map[string]bool{"admin": true, "root": true}
+	xs := xstring.Create().Min(3).Custom("Reserved", func(value string) error {
+		if reserved[value] {
+			return fmt.Errorf("%q is a reserved username", value)
+		}
+		return nil
+	}).Max(8)
+
+	if out := xs.String(); out != "XString(Min,Reserved,Max,)" {
+		t.Errorf("String() -> %s; want XString(Min,Reserved,Max,)", out)
+	}
+
+	if isValid, _ := xs.Validate("john"); !isValid {
+		t.Errorf("Custom(%s) -> false; want true", "john")
+	}
+
+	_, errs := xs.Validate("admin")
+
+	if len(errs) != 1 || errs[0].(*helpers.ValidationError).Rule != "Reserved" || errs[0].Error() != `"admin" is a reserved username` {
+		t.Errorf("Custom(%s) -> %v; want [\"admin\" is a reserved username]", "admin", errs)
+	}
+}
+
+func TestRefine(t *testing.T) {
+	xs := xstring.Create().Refine("NoSpaces", func(value string) bool { return !strings.Contains(value, " ") })
+
+	if isValid, _ := xs.Validate("john"); !isValid {
+		t.Errorf("Refine(%s) -> false; want true", "john")
+	}
+
+	if _, errs := xs.Validate("john doe"); len(errs) != 1 || errs[0].Error() != "is invalid" {
+		t.Errorf("Refine(%s) -> %v; want [is invalid]", "john doe", errs)
+	}
+
+	xs = xstring.Create().Refine("NoSpaces", func(value string) bool { return !strings.Contains(value, " ") }, "no spaces")
+
+	if _, errs := xs.Validate("john doe"); len(errs) != 1 || errs[0].Error() != "no spaces" {
+		t.Errorf("Refine(%s) -> %v; want [no spaces]", "john doe", errs)
+	}
+}
+
+func TestCustomInvalid(t *testing.T) {
+	cases := map[string]func(){
+		"Custom(Reserved, nil)": func() { xstring.Create().Custom("Reserved", nil) },
+		"Refine(NoSpaces, nil)": func() { xstring.Create().Refine("NoSpaces", nil) },
+		"Custom(Required)":      func() { xstring.Create().Custom("Required", func(string) error { return nil }) },
+		"Refine(Email)":         func() { xstring.Create().Refine("Email", func(string) bool { return true }) },
+	}
+
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+
+			f()
+		}()
+	}
+}
@@ -0,0 +1,283 @@
+package xtag
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Rule struct {
+	Name string
+	Args []string
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func Parse(tag string) ([]Rule, error) {
+	p := &parser{input: tag}
+	rules := make([]Rule, 0)
+
+	p.skipSpaces()
+
+	if p.done() {
+		return rules, nil
+	}
+
+	for {
+		rule, err := p.parseRule()
+
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+		p.skipSpaces()
+
+		if p.done() {
+			return rules, nil
+		}
+
+		if p.input[p.pos] != ',' {
+			return nil, p.errorf("expected ',' after rule %q", rule.Name)
+		}
+
+		p.pos++
+	}
+}
+
+func MustParse(tag string) []Rule {
+	rules, err := Parse(tag)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+func (r Rule) String() string {
+	switch len(r.Args) {
+	case 0:
+		return r.Name
+	case 1:
+		return r.Name + "=" + Quote(r.Args[0])
+	}
+
+	args := make([]string, 0, len(r.Args))
+
+	for _, arg := range r.Args {
+		args = append(args, Quote(arg))
+	}
+
+	return r.Name + "(" + strings.Join(args, ",") + ")"
+}
+
+func Quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, ",()[]{}'\\ ") {
+		return arg
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(arg) + "'"
+}
+
+func IsName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isNameChar(name[i]) {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+func (p *parser) parseRule() (Rule, error) {
+	p.skipSpaces()
+	start := p.pos
+
+	for !p.done() && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	rule := Rule{Name: p.input[start:p.pos], Args: make([]string, 0)}
+
+	if rule.Name == "" {
+		return rule, p.errorf("expected rule name")
+	}
+
+	p.skipSpaces()
+
+	if p.done() {
+		return rule, nil
+	}
+
+	switch p.input[p.pos] {
+	case '=':
+		p.pos++
+		arg, err := p.parseValue(false)
+
+		if err != nil {
+			return rule, err
+		}
+
+		rule.Args = append(rule.Args, arg)
+	case '(':
+		p.pos++
+		p.skipSpaces()
+
+		if !p.done() && p.input[p.pos] == ')' {
+			p.pos++
+			return rule, nil
+		}
+
+		for {
+			arg, err := p.parseValue(true)
+
+			if err != nil {
+				return rule, err
+			}
+
+			rule.Args = append(rule.Args, arg)
+
+			if p.done() {
+				return rule, p.errorf("unterminated argument list of rule %q", rule.Name)
+			}
+
+			p.pos++
+
+			if p.input[p.pos-1] == ')' {
+				break
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+func (p *parser) parseValue(inList bool) (string, error) {
+	p.skipSpaces()
+
+	if !p.done() && p.input[p.pos] == '\'' {
+		value, err := p.parseQuoted()
+
+		if err != nil {
+			return "", err
+		}
+
+		p.skipSpaces()
+
+		if !p.done() && p.input[p.pos] != ',' && !(inList && p.input[p.pos] == ')') {
+			return "", p.errorf("unexpected %q after quoted value", p.input[p.pos])
+		}
+
+		return value, nil
+	}
+
+	var value strings.Builder
+	depth := make([]byte, 0)
+
+	for !p.done() {
+		c := p.input[p.pos]
+
+		if len(depth) == 0 && (c == ',' || (inList && c == ')')) {
+			break
+		}
+
+		switch c {
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return "", p.errorf("unterminated escape")
+			}
+
+			p.pos++
+
+			if !isEscapable(p.input[p.pos]) {
+				value.WriteByte(c)
+			}
+
+			c = p.input[p.pos]
+		case '(', '[', '{':
+			depth = append(depth, closing(c))
+		case ')', ']', '}':
+			if len(depth) == 0 || depth[len(depth)-1] != c {
+				return "", p.errorf("unbalanced %q", c)
+			}
+
+			depth = depth[:len(depth)-1]
+		}
+
+		value.WriteByte(c)
+		p.pos++
+	}
+
+	if len(depth) > 0 {
+		return "", p.errorf("missing %q", depth[len(depth)-1])
+	}
+
+	return strings.TrimSpace(value.String()), nil
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	var value strings.Builder
+
+	p.pos++
+
+	for !p.done() {
+		c := p.input[p.pos]
+		p.pos++
+
+		switch c {
+		case '\'':
+			return value.String(), nil
+		case '\\':
+			if p.done() {
+				return "", p.errorf("unterminated escape")
+			}
+
+			if !isEscapable(p.input[p.pos]) {
+				value.WriteByte(c)
+			}
+
+			c = p.input[p.pos]
+			p.pos++
+		}
+
+		value.WriteByte(c)
+	}
+
+	return "", p.errorf("unterminated quoted value")
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("xtag: %s at position %d in %q", fmt.Sprintf(format, args...), p.pos, p.input)
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isEscapable(c byte) bool {
+	return c == '\\' || c == '\'' || c == ','
+}
+
+func closing(c byte) byte {
+	switch c {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	}
+
+	return '}'
+}
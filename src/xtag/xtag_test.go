@@ -0,0 +1,100 @@
+package xtag_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/radchukd/go-xschema/src/xtag"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		tag   string
+		rules []xtag.Rule
+	}{
+		{"", []xtag.Rule{}},
+		{"Required", []xtag.Rule{{"Required", []string{}}}},
+		{"Required, Min=3", []xtag.Rule{{"Required", []string{}}, {"Min", []string{"3"}}}},
+		{"StartsWith=a=b", []xtag.Rule{{"StartsWith", []string{"a=b"}}}},
+		{"StartsWith=", []xtag.Rule{{"StartsWith", []string{""}}}},
+		{"Pattern=^[a-z]{1,3}$,Max=3", []xtag.Rule{{"Pattern", []string{"^[a-z]{1,3}$"}}, {"Max", []string{"3"}}}},
+		{`OneOf=["a","b"]`, []xtag.Rule{{"OneOf", []string{`["a","b"]`}}}},
+		{"Pattern='^a{1,3}$'", []xtag.Rule{{"Pattern", []string{"^a{1,3}$"}}}},
+		{`Pattern='^\d+(\.\d+)?$'`, []xtag.Rule{{"Pattern", []string{`^\d+(\.\d+)?$`}}}},
+		{`Pattern=^\d+$`, []xtag.Rule{{"Pattern", []string{`^\d+$`}}}},
+		{`Pattern=^\[$`, []xtag.Rule{{"Pattern", []string{`^\[$`}}}},
+		{`EndsWith=a\,b`, []xtag.Rule{{"EndsWith", []string{"a,b"}}}},
+		{`EndsWith='it\'s, fine'`, []xtag.Rule{{"EndsWith", []string{"it's, fine"}}}},
+		{"OneOf(a, 'b,c', [1,2])", []xtag.Rule{{"OneOf", []string{"a", "b,c", "[1,2]"}}}},
+		{"When(Kind, card, 'Required,Min=12'),Max=3", []xtag.Rule{{"When", []string{"Kind", "card", "Required,Min=12"}}, {"Max", []string{"3"}}}},
+		{"Items(Required, Min=2)", []xtag.Rule{{"Items", []string{"Required", "Min=2"}}}},
+		{"Unique()", []xtag.Rule{{"Unique", []string{}}}},
+	}
+
+	for _, c := range cases {
+		rules, err := xtag.Parse(c.tag)
+
+		if err != nil {
+			t.Errorf("Parse(%s) -> %v; want nil error", c.tag, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(rules, c.rules) {
+			t.Errorf("Parse(%s) -> %v; want %v", c.tag, rules, c.rules)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tags := []string{
+		",",
+		"Required,",
+		"Min=3 4)",
+		"Pattern='abc",
+		"Pattern=^[a-z$",
+		"Pattern=^a)$",
+		"OneOf(a,b",
+		"OneOf('a' b)",
+		"=3",
+		`EndsWith=a\`,
+	}
+
+	for _, tag := range tags {
+		if rules, err := xtag.Parse(tag); err == nil {
+			t.Errorf("Parse(%s) -> %v; want error", tag, rules)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tags := []string{
+		"Required",
+		"Min=3",
+		"Pattern='^a{1,3}$'",
+		`Pattern='^\\d+$'`,
+		"OneOf(a,'b,c')",
+		`EndsWith='it\'s'`,
+	}
+
+	for _, tag := range tags {
+		rules := xtag.MustParse(tag)
+
+		if out := rules[0].String(); out != tag {
+			t.Errorf("Parse(%s).String() -> %s; want %s", tag, out, tag)
+		}
+
+		if again := xtag.MustParse(rules[0].String()); !reflect.DeepEqual(again, rules) {
+			t.Errorf("Parse(%s) -> %v; want %v", rules[0].String(), again, rules)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustParse(OneOf(a) -> no panic; want panic")
+		}
+	}()
+
+	xtag.MustParse("OneOf(a")
+}